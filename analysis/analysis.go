@@ -0,0 +1,228 @@
+package analysis
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/wesleyholiveira/caesar-challenge/langmodel"
+)
+
+// Family is the kind of cipher a ciphertext most likely comes from
+type Family string
+
+const (
+	// Monoalphabetic ciphers, such as Caesar, replace every letter by the same letter everywhere
+	Monoalphabetic Family = "monoalphabetic"
+	// Polyalphabetic ciphers, such as Vigenère, replace a letter differently depending on its position
+	Polyalphabetic Family = "polyalphabetic"
+	// Transposition ciphers keep the letters and change their order
+	Transposition Family = "transposition"
+	// Plaintext is text that reads as the language already
+	Plaintext Family = "plaintext"
+	// Unknown is reported for texts too short to tell
+	Unknown Family = "unknown"
+)
+
+const (
+	// MinLetters is the number of letters needed to guess the family
+	MinLetters = 20
+	// PolyalphabeticIoC is the index of coincidence below which letters look flattened by several alphabets,
+	// halfway between English (0.066) and uniformly random letters (0.038)
+	PolyalphabeticIoC = 0.052
+	// MinRepeat and MaxRepeat bound the length of the repeated n-grams reported
+	MinRepeat = 3
+	MaxRepeat = 8
+)
+
+// plaintextMargin is how much more likely, per quadgram, text must be than its letters
+// scrambled to be taken as plaintext
+const plaintextMargin = 1.0
+
+// Frequency is the count of a letter in the ciphertext
+type Frequency struct {
+	Letter  string  `json:"letter"`
+	Count   int     `json:"count"`
+	Percent float64 `json:"percent"`
+	// Expected is the percent of the letter in the language
+	Expected float64 `json:"expected"`
+}
+
+// Repeat is an n-gram found more than once, positions count letters only
+type Repeat struct {
+	NGram     string `json:"ngram"`
+	Count     int    `json:"count"`
+	Positions []int  `json:"positions"`
+	// Spacings are the distances between consecutive positions, their common factors hint at a key length
+	Spacings []int `json:"spacings"`
+}
+
+// Report holds the statistics of a ciphertext
+type Report struct {
+	Length             int         `json:"length"`
+	Letters            int         `json:"letters"`
+	Frequencies        []Frequency `json:"frequencies"`
+	IndexOfCoincidence float64     `json:"index_of_coincidence"`
+	// Entropy is the Shannon entropy of the letters in bits per letter
+	Entropy float64  `json:"entropy"`
+	Family  Family   `json:"family"`
+	Repeats []Repeat `json:"repeats"`
+}
+
+// Analyze computes the statistics of text, comparing its letters with the language of model
+func Analyze(text string, model *langmodel.Model) Report {
+	letters := langmodel.Letters(text)
+	r := Report{Length: len([]rune(text)), Letters: len(letters), Frequencies: []Frequency{}}
+
+	var counts [26]int
+	for i := 0; i < len(letters); i++ {
+		counts[letters[i]-'a']++
+	}
+
+	for i, count := range counts {
+		if count == 0 {
+			continue
+		}
+
+		p := float64(count) / float64(len(letters))
+		r.Frequencies = append(r.Frequencies, Frequency{
+			Letter:   string(rune('a' + i)),
+			Count:    count,
+			Percent:  100 * p,
+			Expected: 100 * model.Frequency(rune('a'+i)),
+		})
+
+		r.Entropy -= p * math.Log2(p)
+		if len(letters) > 1 {
+			r.IndexOfCoincidence += float64(count*(count-1)) / float64(len(letters)*(len(letters)-1))
+		}
+	}
+
+	sort.SliceStable(r.Frequencies, func(i, j int) bool {
+		return r.Frequencies[i].Count > r.Frequencies[j].Count
+	})
+
+	r.Family = family(letters, counts, r.IndexOfCoincidence, model)
+	r.Repeats = repeats(letters)
+	return r
+}
+
+// family guesses the kind of cipher from the index of coincidence and how the letters fit the language
+func family(letters string, counts [26]int, ioc float64, model *langmodel.Model) Family {
+	switch {
+	case len(letters) < MinLetters:
+		return Unknown
+	case ioc < PolyalphabeticIoC:
+		return Polyalphabetic
+	case !unshiftedFitsBest(counts, model):
+		return Monoalphabetic
+	}
+
+	quadgrams := len(letters) - langmodel.Quadgram + 1
+	if model.Quadgram(letters)-model.Quadgram(scramble(letters)) > plaintextMargin*float64(quadgrams) {
+		return Plaintext
+	}
+
+	return Transposition
+}
+
+// unshiftedFitsBest reports whether the letter counts fit the language better as they are than
+// rotated by any shift, which holds when the letters were moved around but not replaced
+func unshiftedFitsBest(counts [26]int, model *langmodel.Model) bool {
+	fit := func(places int) float64 {
+		score := 0.0
+		for i, count := range counts {
+			p := model.Frequency(rune('a' + (i+places)%26))
+			if p == 0 {
+				p = 1e-6
+			}
+			score += float64(count) * math.Log(p)
+		}
+
+		return score
+	}
+
+	unshifted := fit(0)
+	for places := 1; places < 26; places++ {
+		if fit(places) > unshifted {
+			return false
+		}
+	}
+
+	return true
+}
+
+// scramble reorders letters by a fixed stride, breaking the n-grams of the language
+func scramble(letters string) string {
+	step := 7
+	for gcd(step, len(letters)) != 1 {
+		step++
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(letters); i++ {
+		b.WriteByte(letters[i*step%len(letters)])
+	}
+
+	return b.String()
+}
+
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+
+	return a
+}
+
+// repeats returns the n-grams found more than once, longest first, leaving out those only
+// found inside a longer repeat
+func repeats(letters string) []Repeat {
+	positions := make(map[string][]int)
+	for n := MinRepeat; n <= MaxRepeat; n++ {
+		for i := 0; i+n <= len(letters); i++ {
+			positions[letters[i:i+n]] = append(positions[letters[i:i+n]], i)
+		}
+	}
+
+	found := []Repeat{}
+	for gram, at := range positions {
+		if len(at) < 2 || insideLonger(gram, len(at), positions) {
+			continue
+		}
+
+		r := Repeat{NGram: gram, Count: len(at), Positions: at}
+		for i := 1; i < len(at); i++ {
+			r.Spacings = append(r.Spacings, at[i]-at[i-1])
+		}
+		found = append(found, r)
+	}
+
+	sort.Slice(found, func(i, j int) bool {
+		if len(found[i].NGram) != len(found[j].NGram) {
+			return len(found[i].NGram) > len(found[j].NGram)
+		}
+		if found[i].Count != found[j].Count {
+			return found[i].Count > found[j].Count
+		}
+
+		return found[i].Positions[0] < found[j].Positions[0]
+	})
+
+	return found
+}
+
+// insideLonger reports whether every occurrence of gram belongs to a repeat one letter longer
+func insideLonger(gram string, count int, positions map[string][]int) bool {
+	if len(gram) == MaxRepeat {
+		return false
+	}
+
+	for _, char := range "abcdefghijklmnopqrstuvwxyz" {
+		if len(positions[gram+string(char)]) == count || len(positions[string(char)+gram]) == count {
+			return true
+		}
+	}
+
+	return false
+}
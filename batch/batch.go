@@ -0,0 +1,165 @@
+package batch
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/wesleyholiveira/caesar-challenge/crypto"
+	"github.com/wesleyholiveira/caesar-challenge/metrics"
+)
+
+// Item is a ciphertext to decrypt, it is cracked when Places is zero
+type Item struct {
+	// Line is the position of the item in the input, starting at 1
+	Line        int    `json:"-"`
+	ID          string `json:"id,omitempty"`
+	CryptedText string `json:"cifrado"`
+	Places      int    `json:"numero_casas,omitempty"`
+	// Err is set by the decoder when the item could not be parsed
+	Err error `json:"-"`
+}
+
+// Result is the decryption of an item, or the reason it failed in Error
+type Result struct {
+	Line          int    `json:"linha"`
+	ID            string `json:"id,omitempty"`
+	Places        int    `json:"numero_casas,omitempty"`
+	CryptedText   string `json:"cifrado"`
+	DecryptedText string `json:"decifrado,omitempty"`
+	SummaryCrypto string `json:"resumo_criptografico,omitempty"`
+	Error         string `json:"error,omitempty"`
+}
+
+// Stats counts the items processed by a batch
+type Stats struct {
+	Items  int
+	Failed int
+}
+
+// Decoder returns the items of an input one at a time, and io.EOF after the last one.
+// Items that cannot be parsed are returned with Err set, other errors stop the batch.
+type Decoder interface {
+	Next() (Item, error)
+}
+
+// Encoder writes results to an output
+type Encoder interface {
+	Encode(Result) error
+	Flush() error
+}
+
+// Decrypt decrypts the item, cracking it when it has no shift
+func Decrypt(item Item) Result {
+	result := Result{Line: item.Line, ID: item.ID, Places: item.Places, CryptedText: item.CryptedText}
+
+	switch {
+	case item.Err != nil:
+		result.Error = item.Err.Error()
+		return result
+	case item.CryptedText == "":
+		result.Error = "cifrado is required"
+		return result
+	case item.Places != 0 && (item.Places < crypto.MinPlaces || item.Places > crypto.MaxPlaces):
+		result.Error = fmt.Sprintf("numero_casas %d is out of range [%d, %d]", item.Places, crypto.MinPlaces, crypto.MaxPlaces)
+		return result
+	}
+
+	if result.Places == 0 {
+		candidates := crypto.Crack(item.CryptedText)
+		metrics.ObserveCrack(len(candidates))
+		result.Places = candidates[0].Places
+	}
+
+	metrics.ObserveDecrypt(item.CryptedText)
+	result.DecryptedText = crypto.DecryptText(item.CryptedText, result.Places)
+	result.SummaryCrypto = crypto.Summary(result.DecryptedText)
+	return result
+}
+
+// Process decrypts every item of dec with at most workers goroutines and passes the
+// results to emit in input order. Failed items are counted and emitted with their
+// error, the batch only stops on read errors or when emit fails.
+func Process(dec Decoder, workers int, emit func(Result) error) (Stats, error) {
+	if workers < 1 {
+		workers = 1
+	}
+
+	type job struct {
+		item   Item
+		result chan Result
+	}
+
+	jobs := make(chan job)
+	// pending keeps the results in input order, its buffer bounds how far workers run ahead
+	pending := make(chan chan Result, workers*2)
+	done := make(chan struct{})
+	var readErr error
+
+	go func() {
+		defer close(pending)
+		defer close(jobs)
+
+		for {
+			item, err := dec.Next()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+
+			if err != nil {
+				readErr = err
+				return
+			}
+
+			j := job{item: item, result: make(chan Result, 1)}
+			select {
+			case pending <- j.result:
+			case <-done:
+				return
+			}
+
+			jobs <- j
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				j.result <- Decrypt(j.item)
+			}
+		}()
+	}
+
+	stats := Stats{}
+	var emitErr error
+	for result := range pending {
+		r := <-result
+		stats.Items++
+		if r.Error != "" {
+			stats.Failed++
+		}
+
+		if emitErr = emit(r); emitErr != nil {
+			close(done)
+			break
+		}
+	}
+
+	if emitErr != nil {
+		// let the reader and the workers finish the jobs already queued
+		for range pending {
+		}
+	}
+
+	wg.Wait()
+
+	if emitErr != nil {
+		return stats, emitErr
+	}
+
+	return stats, readErr
+}
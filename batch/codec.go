@@ -0,0 +1,184 @@
+package batch
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Kinds of batch files, JSONL holds one JSON object per line and CSV starts with a header row
+const (
+	JSONL = "jsonl"
+	CSV   = "csv"
+)
+
+// KindForFile returns CSV for .csv files and JSONL otherwise, ignoring a .gz suffix
+func KindForFile(name string) string {
+	ext := strings.ToLower(filepath.Ext(strings.TrimSuffix(name, ".gz")))
+	if ext == ".csv" {
+		return CSV
+	}
+
+	return JSONL
+}
+
+// NewDecoder returns a decoder reading items of the given kind from r
+func NewDecoder(r io.Reader, kind string) (Decoder, error) {
+	switch kind {
+	case JSONL:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+		return &jsonlDecoder{scanner: scanner}, nil
+	case CSV:
+		cr := csv.NewReader(r)
+		cr.FieldsPerRecord = -1
+		return &csvDecoder{reader: cr}, nil
+	}
+
+	return nil, fmt.Errorf("unknown batch format %q, expected %s or %s", kind, JSONL, CSV)
+}
+
+// NewEncoder returns an encoder writing results of the given kind to w
+func NewEncoder(w io.Writer, kind string) (Encoder, error) {
+	switch kind {
+	case JSONL:
+		buf := bufio.NewWriter(w)
+		return &jsonlEncoder{buf: buf, encoder: json.NewEncoder(buf)}, nil
+	case CSV:
+		return &csvEncoder{writer: csv.NewWriter(w)}, nil
+	}
+
+	return nil, fmt.Errorf("unknown batch format %q, expected %s or %s", kind, JSONL, CSV)
+}
+
+type jsonlDecoder struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func (d *jsonlDecoder) Next() (Item, error) {
+	for d.scanner.Scan() {
+		d.line++
+		text := strings.TrimSpace(d.scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		item := Item{}
+		if err := json.Unmarshal([]byte(text), &item); err != nil {
+			item = Item{Err: err}
+		}
+
+		item.Line = d.line
+		return item, nil
+	}
+
+	if err := d.scanner.Err(); err != nil {
+		return Item{}, err
+	}
+
+	return Item{}, io.EOF
+}
+
+type csvDecoder struct {
+	reader  *csv.Reader
+	columns map[string]int
+}
+
+func (d *csvDecoder) Next() (Item, error) {
+	if d.columns == nil {
+		header, err := d.reader.Read()
+		if err != nil {
+			return Item{}, err
+		}
+
+		d.columns = map[string]int{}
+		for i, name := range header {
+			d.columns[strings.TrimSpace(name)] = i
+		}
+
+		if _, ok := d.columns["cifrado"]; !ok {
+			return Item{}, errors.New("csv: header has no cifrado column")
+		}
+	}
+
+	record, err := d.reader.Read()
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return Item{Line: parseErr.StartLine, Err: err}, nil
+	}
+
+	if err != nil {
+		return Item{}, err
+	}
+
+	// FieldPos panics unless the last Read returned a record
+	line, _ := d.reader.FieldPos(0)
+	item := Item{Line: line, ID: d.field(record, "id"), CryptedText: d.field(record, "cifrado")}
+	if places := d.field(record, "numero_casas"); places != "" {
+		if item.Places, err = strconv.Atoi(places); err != nil {
+			item.Err = fmt.Errorf("numero_casas: %v", err)
+		}
+	}
+
+	return item, nil
+}
+
+// field returns the named column of the record, or an empty string when it is missing
+func (d *csvDecoder) field(record []string, name string) string {
+	i, ok := d.columns[name]
+	if !ok || i >= len(record) {
+		return ""
+	}
+
+	return record[i]
+}
+
+type jsonlEncoder struct {
+	buf     *bufio.Writer
+	encoder *json.Encoder
+}
+
+func (e *jsonlEncoder) Encode(r Result) error {
+	return e.encoder.Encode(r)
+}
+
+func (e *jsonlEncoder) Flush() error {
+	return e.buf.Flush()
+}
+
+// csvHeader lists the columns of CSV results, matching the json tags of Result
+var csvHeader = []string{"linha", "id", "numero_casas", "cifrado", "decifrado", "resumo_criptografico", "error"}
+
+type csvEncoder struct {
+	writer *csv.Writer
+	header bool
+}
+
+func (e *csvEncoder) Encode(r Result) error {
+	if !e.header {
+		if err := e.writer.Write(csvHeader); err != nil {
+			return err
+		}
+
+		e.header = true
+	}
+
+	places := ""
+	if r.Places != 0 {
+		places = strconv.Itoa(r.Places)
+	}
+
+	return e.writer.Write([]string{strconv.Itoa(r.Line), r.ID, places, r.CryptedText, r.DecryptedText, r.SummaryCrypto, r.Error})
+}
+
+func (e *csvEncoder) Flush() error {
+	e.writer.Flush()
+	return e.writer.Error()
+}
@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/wesleyholiveira/caesar-challenge/crypto"
+	"github.com/wesleyholiveira/caesar-challenge/metrics"
+)
+
+func setupAffine(a *App, fs *flag.FlagSet) func([]string) error {
+	in := fs.String("in", "", "read the text from this file, or the standard input for -, instead of the arguments")
+	keyA := fs.Int("a", 1, "multiplier of the key, coprime with the alphabet size")
+	keyB := fs.Int("b", 0, "offset of the key")
+	chars := fs.String("alphabet", crypto.LowerAlphabet, "alphabet the cipher works on: latin, alphanumeric, printable, cyrillic, greek or its characters in order")
+	decrypt := fs.Bool("decrypt", false, "decrypt the text instead of encrypting it")
+	crack := fs.Bool("crack", false, "rank every valid key by English letter frequencies instead of using -a and -b")
+	top := fs.Int("top", 5, "number of candidates printed by -crack, 0 prints all of them")
+
+	return func(args []string) error {
+		if *in == "" && len(args) == 0 {
+			return usagef("missing text")
+		}
+
+		text, err := a.readInput(*in, args)
+		if err != nil {
+			return err
+		}
+
+		if *crack {
+			candidates, err := crypto.CrackAffine(text, *chars)
+			if err != nil {
+				return usagef("%v", err)
+			}
+
+			metrics.ObserveCrack(len(candidates))
+			if *top > 0 && *top < len(candidates) {
+				candidates = candidates[:*top]
+			}
+
+			for _, c := range candidates {
+				fmt.Fprintf(a.Stdout, "%2d\t%2d\t%.2f\t%s\n", c.Key.A, c.Key.B, c.Score, c.Text)
+			}
+
+			return nil
+		}
+
+		key := crypto.AffineKey{A: *keyA, B: *keyB, Alphabet: *chars}
+		var out string
+		if *decrypt {
+			out, err = crypto.DecryptAffine(text, key)
+		} else {
+			out, err = crypto.EncryptAffine(text, key)
+		}
+
+		if err != nil {
+			return usagef("%v", err)
+		}
+
+		fmt.Fprintln(a.Stdout, out)
+		return nil
+	}
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/wesleyholiveira/caesar-challenge/analysis"
+	"github.com/wesleyholiveira/caesar-challenge/format"
+	"github.com/wesleyholiveira/caesar-challenge/langmodel"
+	"github.com/wesleyholiveira/caesar-challenge/reader"
+)
+
+func setupAnalyze(a *App, fs *flag.FlagSet) func([]string) error {
+	file, answerFormat := answerFlags(fs)
+	in := fs.String("in", "", "analyze this file, or the standard input for -, instead of the answer file")
+	output := fs.String("output", "text", "output of the report: text or json")
+	lang := fs.String("lang", langmodel.English, "language the letters are compared with: en, pt or a file saved by train")
+	top := fs.Int("top", 10, "number of repeated n-grams to print, 0 prints all of them")
+
+	return func(args []string) error {
+		if *output != "text" && *output != "json" {
+			return usagef("unknown output %q, expected text or json", *output)
+		}
+
+		model, err := langmodel.Lookup(*lang)
+		if err != nil {
+			return err
+		}
+
+		var text string
+		if *in != "" || len(args) > 0 {
+			if text, err = a.readInput(*in, args); err != nil {
+				return err
+			}
+		} else {
+			f, err := answerFormat(*file)
+			if err != nil {
+				return err
+			}
+
+			response, err := reader.ReadChallenge(*file, f)
+			if err != nil {
+				return err
+			}
+			text = response.CryptedText
+		}
+
+		report := analysis.Analyze(text, model)
+		if *top > 0 && *top < len(report.Repeats) {
+			report.Repeats = report.Repeats[:*top]
+		}
+
+		if *output == "json" {
+			data, err := format.JSON.Encode(report)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintln(a.Stdout, string(data))
+			return nil
+		}
+
+		printReport(a, report)
+		return nil
+	}
+}
+
+// printReport writes the report as a summary followed by the frequency and repeat tables
+func printReport(a *App, r analysis.Report) {
+	fmt.Fprintf(a.Stdout, "length\t%d\n", r.Length)
+	fmt.Fprintf(a.Stdout, "letters\t%d\n", r.Letters)
+	fmt.Fprintf(a.Stdout, "ioc\t%.4f\n", r.IndexOfCoincidence)
+	fmt.Fprintf(a.Stdout, "entropy\t%.3f bits\n", r.Entropy)
+	fmt.Fprintf(a.Stdout, "family\t%s\n", r.Family)
+
+	fmt.Fprintln(a.Stdout)
+	fmt.Fprintln(a.Stdout, "letter\tcount\t%\texpected %")
+	for _, f := range r.Frequencies {
+		fmt.Fprintf(a.Stdout, "%s\t%d\t%.2f\t%.2f\t%s\n", f.Letter, f.Count, f.Percent, f.Expected, strings.Repeat("#", int(f.Percent+0.5)))
+	}
+
+	if len(r.Repeats) == 0 {
+		return
+	}
+
+	fmt.Fprintln(a.Stdout)
+	fmt.Fprintln(a.Stdout, "repeat\tcount\tspacings")
+	for _, rep := range r.Repeats {
+		spacings := make([]string, len(rep.Spacings))
+		for i, s := range rep.Spacings {
+			spacings[i] = fmt.Sprint(s)
+		}
+
+		fmt.Fprintf(a.Stdout, "%s\t%d\t%s\n", rep.NGram, rep.Count, strings.Join(spacings, " "))
+	}
+}
@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+
+	"github.com/wesleyholiveira/caesar-challenge/batch"
+	"github.com/wesleyholiveira/caesar-challenge/reader"
+)
+
+func setupBatch(a *App, fs *flag.FlagSet) func([]string) error {
+	in := fs.String("in", reader.Stdin, "JSONL or CSV file of items with cifrado and an optional numero_casas, - reads the standard input")
+	out := fs.String("out", reader.Stdin, "file the results are written to, - writes to the standard output")
+	inFormat := fs.String("in-format", "", "format of -in: jsonl or csv, defaults to the file extension")
+	outFormat := fs.String("out-format", "", "format of -out: jsonl or csv, defaults to the file extension or to -in-format")
+	workers := fs.Int("workers", runtime.NumCPU(), "number of items decrypted concurrently")
+
+	return func(args []string) error {
+		if err := noArgs(args); err != nil {
+			return err
+		}
+
+		if *workers < 1 {
+			return usagef("-workers must be at least 1")
+		}
+
+		if *inFormat == "" {
+			*inFormat = batch.KindForFile(*in)
+		}
+
+		if *outFormat == "" {
+			*outFormat = *inFormat
+			if *out != reader.Stdin {
+				*outFormat = batch.KindForFile(*out)
+			}
+		}
+
+		var src io.ReadCloser
+		var err error
+		if *in == reader.Stdin {
+			src, err = reader.Decompress(a.Stdin)
+		} else {
+			src, err = reader.Open(*in)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		defer src.Close()
+
+		dec, err := batch.NewDecoder(src, *inFormat)
+		if err != nil {
+			return usagef("%v", err)
+		}
+
+		dst := a.Stdout
+		var file *os.File
+		if *out != reader.Stdin {
+			if file, err = os.Create(*out); err != nil {
+				return err
+			}
+
+			defer file.Close()
+			dst = file
+		}
+
+		enc, err := batch.NewEncoder(dst, *outFormat)
+		if err != nil {
+			return usagef("%v", err)
+		}
+
+		stats, err := batch.Process(dec, *workers, func(r batch.Result) error {
+			if r.Error != "" {
+				fmt.Fprintf(a.Stderr, "%s:%d: %s\n", *in, r.Line, r.Error)
+			}
+
+			return enc.Encode(r)
+		})
+
+		if flushErr := enc.Flush(); err == nil {
+			err = flushErr
+		}
+
+		if file != nil {
+			if closeErr := file.Close(); err == nil {
+				err = closeErr
+			}
+		}
+
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(a.Stderr, "%d items, %d failed\n", stats.Items, stats.Failed)
+		if stats.Failed > 0 {
+			return fmt.Errorf("%d of %d items failed", stats.Failed, stats.Items)
+		}
+
+		return nil
+	}
+}
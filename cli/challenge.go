@@ -0,0 +1,300 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/wesleyholiveira/caesar-challenge/crypto"
+	"github.com/wesleyholiveira/caesar-challenge/history"
+	"github.com/wesleyholiveira/caesar-challenge/metrics"
+	"github.com/wesleyholiveira/caesar-challenge/model"
+	"github.com/wesleyholiveira/caesar-challenge/reader"
+	"github.com/wesleyholiveira/caesar-challenge/request"
+	"github.com/wesleyholiveira/caesar-challenge/runner"
+	"github.com/wesleyholiveira/caesar-challenge/verify"
+	"github.com/wesleyholiveira/caesar-challenge/writer"
+)
+
+func setupRun(a *App, fs *flag.FlagSet) func([]string) error {
+	file, answerFormat := answerFlags(fs)
+	force := fs.Bool("force", false, "submit even when the answer does not verify")
+	dryRun := fs.Bool("dry-run", false, "print the submission with the token masked instead of sending it")
+	load := fs.Bool("load", false, "use the challenge held by the answer file instead of fetching a new one")
+	fresh := fs.Bool("fresh", false, "fetch a new challenge instead of resuming an interrupted run")
+	resubmit := fs.Bool("resubmit", false, "submit again an answer that was submitted or whose submit was interrupted")
+	store := historyFlags(fs)
+
+	return func(args []string) error {
+		if err := noArgs(args); err != nil {
+			return err
+		}
+
+		f, err := answerFormat(*file)
+		if err != nil {
+			return err
+		}
+
+		announced := false
+		result, err := runner.RunContext(a.ctx, runner.Options{
+			File:        *file,
+			Format:      f,
+			Force:       *force,
+			Load:        *load,
+			DryRun:      *dryRun,
+			Fresh:       *fresh,
+			Resubmit:    *resubmit,
+			History:     store(),
+			GetRequest:  a.GetRequest,
+			PostRequest: a.PostRequest,
+			Logger:      a.logger,
+			Progress: func(step runner.Step, result *runner.Result) {
+				if result.Resumed != "" && !announced {
+					fmt.Fprintf(a.Stderr, "resuming run %s after %s\n", result.RunID, result.Resumed)
+					announced = true
+				}
+
+				fmt.Fprintf(a.Stderr, "%s: done\n", step)
+			},
+		})
+		if result != nil && result.Report != nil && !result.Report.OK() && (*force || *dryRun) {
+			fmt.Fprintf(a.Stderr, "unverified answer: %s\n", result.Report)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if *dryRun {
+			fmt.Fprintln(a.Stdout, result.Payload.Masked())
+			return nil
+		}
+
+		fmt.Fprintln(a.Stdout, string(result.Response))
+		return nil
+	}
+}
+
+func setupFetch(a *App, fs *flag.FlagSet) func([]string) error {
+	file, answerFormat := answerFlags(fs)
+	store := historyFlags(fs)
+
+	return func(args []string) error {
+		if err := noArgs(args); err != nil {
+			return err
+		}
+
+		f, err := answerFormat(*file)
+		if err != nil {
+			return err
+		}
+
+		event := history.Event{RunID: history.NewRunID(), Step: string(runner.Fetch)}
+		w, err := request.GetCryptedTextContext(a.runContext(event.RunID), *file, f, a.GetRequest, nil)
+		if err != nil {
+			event.Error = err.Error()
+		} else {
+			event.Answer = w.Response.(*model.ChallengeResponse)
+			// the new challenge replaces any interrupted run held by the answer file
+			if err := runner.WriteState(*file, runner.State{RunID: event.RunID, Step: runner.Fetch}); err != nil {
+				return err
+			}
+		}
+
+		if err := appendEvent(store(), event); err != nil {
+			return err
+		}
+
+		if err != nil {
+			return &runner.StepError{Step: runner.Fetch, Err: err}
+		}
+
+		fmt.Fprintln(a.Stdout, *file)
+		return nil
+	}
+}
+
+func setupDecrypt(a *App, fs *flag.FlagSet) func([]string) error {
+	file, answerFormat := answerFlags(fs)
+	places := fs.Int("places", 0, "shift to decrypt with, defaults to numero_casas of the answer or to cracking the text")
+	in := fs.String("in", "", "decrypt this file, or the standard input for -, instead of the answer file, defaults to - when the input is piped and -answer is not given")
+	output := fs.String("output", "text", "output of text decryption: text or json")
+	spec := pipelineFlag(fs, "decrypt with this pipeline instead of a shift, undoing its stages last first")
+	shift := alphabetFlags(fs)
+
+	return func(args []string) error {
+		p, err := parsePipeline(*spec)
+		if err != nil {
+			return err
+		}
+
+		// piped input is decrypted as a filter, leaving the answer file untouched
+		input := *in
+		if input == "" && len(args) == 0 && !isSet(fs, "answer") && piped(a.Stdin) {
+			input = reader.Stdin
+		}
+
+		if input != "" || len(args) > 0 {
+			return a.decryptText(input, args, *places, p, shift, *output)
+		}
+
+		f, err := answerFormat(*file)
+		if err != nil {
+			return err
+		}
+
+		response, err := reader.ReadChallenge(*file, f)
+		if err != nil {
+			return err
+		}
+
+		if *places != 0 {
+			response.Places = *places
+		}
+
+		if p == nil && shift.custom() {
+			// the pipeline recorded in the answer keeps the unknown characters
+			if *shift.unknown == crypto.DropUnknown.String() {
+				return usagef("-unknown drop cannot be verified in the answer file, use -in or text arguments")
+			}
+
+			stage, err := shift.stage(response.Places)
+			if err != nil {
+				return err
+			}
+			p = crypto.Pipeline{stage}
+		}
+
+		w := writer.New()
+		w.File = *file
+		w.Format = f
+		w.Response = response
+		w.Logger = a.logger
+		if p != nil {
+			err = crypto.DecryptPipelineContext(a.ctx, w, p)
+		} else {
+			err = crypto.DecryptContext(a.ctx, w)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		metrics.ObserveDecrypt(response.CryptedText)
+		fmt.Fprintln(a.Stdout, response.DecryptedText)
+		return nil
+	}
+}
+
+func setupVerify(a *App, fs *flag.FlagSet) func([]string) error {
+	file, answerFormat := answerFlags(fs)
+
+	return func(args []string) error {
+		if err := noArgs(args); err != nil {
+			return err
+		}
+
+		f, err := answerFormat(*file)
+		if err != nil {
+			return err
+		}
+
+		report, err := verify.File(*file, f)
+		if err != nil {
+			return err
+		}
+
+		for _, d := range report.Diffs {
+			fmt.Fprintln(a.Stdout, d)
+		}
+
+		if report.OK() {
+			fmt.Fprintf(a.Stdout, "%s: ok\n", *file)
+		}
+
+		return report.Err()
+	}
+}
+
+func setupSubmit(a *App, fs *flag.FlagSet) func([]string) error {
+	file, answerFormat := answerFlags(fs)
+	force := fs.Bool("force", false, "submit even when the answer does not verify or was already submitted")
+	store := historyFlags(fs)
+
+	return func(args []string) error {
+		if err := noArgs(args); err != nil {
+			return err
+		}
+
+		f, err := answerFormat(*file)
+		if err != nil {
+			return err
+		}
+
+		report, err := verify.File(*file, f)
+		if err != nil {
+			return err
+		}
+
+		if !report.OK() {
+			if !*force {
+				return report.Err()
+			}
+
+			fmt.Fprintf(a.Stderr, "submitting unverified answer: %s\n", report)
+		}
+
+		state, err := runner.ReadState(*file)
+		if err != nil {
+			return err
+		}
+
+		if state != nil && !*force {
+			switch state.Step {
+			case runner.Submitting:
+				return fmt.Errorf("%w: run %s, check the history and use -force to submit again", runner.ErrSubmitUncertain, state.RunID)
+			case runner.Submit:
+				return fmt.Errorf("%w: run %s, use -force to submit again", runner.ErrAlreadySubmitted, state.RunID)
+			}
+		}
+
+		if state == nil {
+			state = &runner.State{RunID: history.NewRunID()}
+		}
+
+		if err := runner.WriteState(*file, runner.State{RunID: state.RunID, Step: runner.Submitting}); err != nil {
+			return err
+		}
+
+		respBody, err := request.PostSubmitDataContext(a.runContext(state.RunID), *file, f, a.PostRequest)
+		event := history.Event{RunID: state.RunID, Step: string(runner.Submit), Answer: report.Answer, Response: string(respBody)}
+		next := runner.Submit
+		if err != nil {
+			event.Error = err.Error()
+			next = runner.Verify
+		}
+
+		if err := runner.WriteState(*file, runner.State{RunID: state.RunID, Step: next}); err != nil {
+			return err
+		}
+
+		if err := appendEvent(store(), event); err != nil {
+			return err
+		}
+
+		if err != nil {
+			return &runner.StepError{Step: runner.Submit, Err: err}
+		}
+
+		fmt.Fprintln(a.Stdout, string(respBody))
+		return nil
+	}
+}
+
+// appendEvent records the event when the history is enabled
+func appendEvent(store *history.Store, e history.Event) error {
+	if store == nil {
+		return nil
+	}
+
+	return store.Append(e)
+}
@@ -0,0 +1,319 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/wesleyholiveira/caesar-challenge/config"
+	"github.com/wesleyholiveira/caesar-challenge/crypto"
+	"github.com/wesleyholiveira/caesar-challenge/format"
+	"github.com/wesleyholiveira/caesar-challenge/langmodel"
+	"github.com/wesleyholiveira/caesar-challenge/metrics"
+	"github.com/wesleyholiveira/caesar-challenge/reader"
+	"github.com/wesleyholiveira/caesar-challenge/writer"
+)
+
+// inputText joins the arguments, or reads the standard input when there are none
+func (a *App) inputText(args []string) (string, error) {
+	if len(args) > 0 {
+		return strings.Join(args, " "), nil
+	}
+
+	data, err := reader.ReadLimit(a.Stdin, config.MaxInputSize)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(string(data), "\n"), nil
+}
+
+// piped reports whether r carries input, a pipe or a redirected file, rather than a terminal
+// or a device such as /dev/null. Readers other than files, as injected by tests, count as piped.
+func piped(r io.Reader) bool {
+	f, ok := r.(*os.File)
+	if !ok {
+		return true
+	}
+
+	stat, err := f.Stat()
+	return err == nil && (stat.Mode().IsRegular() || stat.Mode()&(os.ModeNamedPipe|os.ModeSocket) != 0)
+}
+
+// isSet reports whether the named flag was given on the command line
+func isSet(fs *flag.FlagSet, name string) bool {
+	set := false
+	fs.Visit(func(f *flag.Flag) {
+		set = set || f.Name == name
+	})
+
+	return set
+}
+
+// readInput reads the named file, the standard input for reader.Stdin, or joins the arguments
+func (a *App) readInput(name string, args []string) (string, error) {
+	if name == "" {
+		return strings.Join(args, " "), nil
+	}
+
+	if len(args) > 0 {
+		return "", usagef("text arguments cannot be used with -in")
+	}
+
+	var data []byte
+	if name == reader.Stdin {
+		r, err := reader.Decompress(a.Stdin)
+		if err != nil {
+			return "", err
+		}
+
+		if data, err = reader.ReadLimit(r, config.MaxInputSize); err != nil {
+			return "", err
+		}
+	} else {
+		var err error
+		if data, err = reader.ReadFile(name, config.MaxInputSize); err != nil {
+			return "", err
+		}
+	}
+
+	return strings.TrimRight(string(data), "\n"), nil
+}
+
+// decryption is the JSON output of decrypting text outside of a challenge
+type decryption struct {
+	Places        int    `json:"numero_casas"`
+	CryptedText   string `json:"cifrado"`
+	DecryptedText string `json:"decifrado"`
+	SummaryCrypto string `json:"resumo_criptografico"`
+}
+
+// decryptText decrypts the input with the pipeline, or with places, cracking it when places is
+// zero, and prints the plaintext followed by its SHA-1 or both as a JSON object
+func (a *App) decryptText(in string, args []string, places int, p crypto.Pipeline, shift *alphabetOptions, output string) error {
+	if output != "text" && output != "json" {
+		return usagef("unknown output %q, expected text or json", output)
+	}
+
+	text, err := a.readInput(in, args)
+	if err != nil {
+		return err
+	}
+
+	d := decryption{CryptedText: text}
+	switch {
+	case p != nil:
+		if d.DecryptedText, err = p.Decrypt(text); err != nil {
+			return err
+		}
+	case shift.custom():
+		alphabet, err := crypto.LookupAlphabet(*shift.alphabet)
+		if err != nil {
+			return usagef("%v", err)
+		}
+
+		if places == 0 {
+			if alphabet.Scorer() == nil {
+				return usagef("-places is required with alphabet %q, its letter frequencies are unknown", *shift.alphabet)
+			}
+
+			candidates := crypto.CrackAlphabet(text, alphabet, alphabet.Scorer())
+			metrics.ObserveCrack(len(candidates))
+			places = candidates[0].Places
+		}
+
+		stage, err := shift.stage(places)
+		if err != nil {
+			return err
+		}
+
+		d.Places = places
+		if d.DecryptedText, err = stage.Decrypt(text); err != nil {
+			return err
+		}
+	default:
+		if places == 0 {
+			candidates := crypto.Crack(text)
+			metrics.ObserveCrack(len(candidates))
+			places = candidates[0].Places
+		}
+
+		d.Places = places
+		d.DecryptedText = crypto.DecryptText(text, places)
+	}
+
+	metrics.ObserveDecrypt(text)
+	d.SummaryCrypto = crypto.Summary(d.DecryptedText)
+
+	if output == "json" {
+		data, err := format.JSON.Encode(d)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintln(a.Stdout, string(data))
+		return nil
+	}
+
+	fmt.Fprintln(a.Stdout, d.DecryptedText)
+	fmt.Fprintln(a.Stdout, d.SummaryCrypto)
+	return nil
+}
+
+func setupEncrypt(a *App, fs *flag.FlagSet) func([]string) error {
+	places := fs.Int("places", 3, "shift to encrypt with")
+	spec := pipelineFlag(fs, "encrypt with this pipeline instead of a shift, running its stages in order")
+	shift := alphabetFlags(fs)
+
+	return func(args []string) error {
+		p, err := parsePipeline(*spec)
+		if err != nil {
+			return err
+		}
+
+		text, err := a.inputText(args)
+		if err != nil {
+			return err
+		}
+
+		if p == nil && !shift.custom() {
+			fmt.Fprintln(a.Stdout, crypto.EncryptText(text, *places))
+			return nil
+		}
+
+		if p == nil {
+			stage, err := shift.stage(*places)
+			if err != nil {
+				return err
+			}
+			p = crypto.Pipeline{stage}
+		}
+
+		if text, err = p.Encrypt(text); err != nil {
+			return err
+		}
+
+		fmt.Fprintln(a.Stdout, text)
+		return nil
+	}
+}
+
+// pipelineFlag registers the -pipeline flag holding a spec such as caesar:3|reverse|base64
+func pipelineFlag(fs *flag.FlagSet, usage string) *string {
+	return fs.String("pipeline", "", usage+", such as caesar:3|reverse|base64. Stages: "+strings.Join(crypto.StageNames(), ", "))
+}
+
+// alphabetOptions are the flags shifting along another alphabet than a-z
+type alphabetOptions struct {
+	alphabet *string
+	unknown  *string
+}
+
+func alphabetFlags(fs *flag.FlagSet) *alphabetOptions {
+	return &alphabetOptions{
+		alphabet: fs.String("alphabet", "", "alphabet to shift along: latin, alphanumeric, printable (ROT47 with -places 47), cyrillic, greek or its characters in order, defaults to a-z"),
+		unknown:  fs.String("unknown", crypto.KeepUnknown.String(), "characters missing from the alphabet: keep, drop or error"),
+	}
+}
+
+// custom reports whether the flags ask for anything else than shifting a-z and keeping other characters
+func (o *alphabetOptions) custom() bool {
+	return *o.alphabet != "" || *o.unknown != crypto.KeepUnknown.String()
+}
+
+// stage returns the Caesar stage shifting by places as set by the flags
+func (o *alphabetOptions) stage(places int) (crypto.Stage, error) {
+	if _, err := crypto.LookupAlphabet(*o.alphabet); err != nil {
+		return nil, usagef("%v", err)
+	}
+
+	unknown, err := crypto.ParseUnknown(*o.unknown)
+	if err != nil {
+		return nil, usagef("%v", err)
+	}
+
+	return crypto.CaesarStage{Places: places, Alphabet: *o.alphabet, Unknown: unknown}, nil
+}
+
+// parsePipeline returns the pipeline of spec, or nil when it is empty
+func parsePipeline(spec string) (crypto.Pipeline, error) {
+	if spec == "" {
+		return nil, nil
+	}
+
+	p, err := crypto.ParsePipeline(spec)
+	if err != nil {
+		return nil, usagef("%v", err)
+	}
+
+	return p, nil
+}
+
+func setupCrack(a *App, fs *flag.FlagSet) func([]string) error {
+	file, answerFormat := answerFlags(fs)
+	top := fs.Int("top", 5, "number of candidates to print, 0 prints all of them")
+	write := fs.Bool("write", false, "decrypt the answer file with the best shift, only without text arguments")
+	lang := fs.String("lang", "", "language model ranking the shifts: en, pt or a file saved by train, defaults to English letter frequencies")
+
+	return func(args []string) error {
+		if len(args) > 0 && *write {
+			return usagef("-write cannot be used with text arguments")
+		}
+
+		score := crypto.Scorer(crypto.Score)
+		if *lang != "" {
+			model, err := langmodel.Lookup(*lang)
+			if err != nil {
+				return err
+			}
+			score = model.Score
+		}
+
+		if len(args) > 0 {
+			return printCandidates(a, crypto.CrackWith(strings.Join(args, " "), score), *top)
+		}
+
+		f, err := answerFormat(*file)
+		if err != nil {
+			return err
+		}
+
+		response, err := reader.ReadChallenge(*file, f)
+		if err != nil {
+			return err
+		}
+
+		candidates := crypto.CrackWith(response.CryptedText, score)
+		if *write {
+			response.Places = candidates[0].Places
+
+			w := writer.New()
+			w.Logger = a.logger
+			w.File = *file
+			w.Format = f
+			w.Response = response
+			if err := crypto.Decrypt(w); err != nil {
+				return err
+			}
+			metrics.ObserveDecrypt(response.CryptedText)
+		}
+
+		return printCandidates(a, candidates, *top)
+	}
+}
+
+// printCandidates writes the first top candidates as shift, score and text columns
+func printCandidates(a *App, candidates []crypto.Candidate, top int) error {
+	metrics.ObserveCrack(len(candidates))
+	if top > 0 && top < len(candidates) {
+		candidates = candidates[:top]
+	}
+
+	for _, c := range candidates {
+		fmt.Fprintf(a.Stdout, "%2d\t%.2f\t%s\n", c.Places, c.Score, c.Text)
+	}
+
+	return nil
+}
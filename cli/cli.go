@@ -0,0 +1,350 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/wesleyholiveira/caesar-challenge/config"
+	"github.com/wesleyholiveira/caesar-challenge/format"
+	"github.com/wesleyholiveira/caesar-challenge/history"
+	"github.com/wesleyholiveira/caesar-challenge/logging"
+	"github.com/wesleyholiveira/caesar-challenge/metrics"
+	"github.com/wesleyholiveira/caesar-challenge/runner"
+	"github.com/wesleyholiveira/caesar-challenge/tracing"
+	"github.com/wesleyholiveira/caesar-challenge/verify"
+)
+
+// Exit codes returned by Run
+const (
+	ExitOK = iota
+	// ExitError is a generic failure
+	ExitError
+	// ExitUsage is an unknown command, a bad flag or missing arguments
+	ExitUsage
+	// ExitUnverified is an answer whose fields disagree with the recomputed ones
+	ExitUnverified
+	// ExitRequest is a failed call to the challenge API
+	ExitRequest
+)
+
+// App runs the commands, its streams and request functions can be replaced for tests
+type App struct {
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+	// GetRequest and PostRequest default to the request package http calls when nil
+	GetRequest  func(string) ([]byte, error)
+	PostRequest func(string, *bytes.Buffer) ([]byte, error)
+
+	// logger writes to Stderr as set by the -log-level and -log-format flags of the command
+	logger *slog.Logger
+	// ctx holds the span of the running command
+	ctx context.Context
+}
+
+// New returns an App bound to the standard streams
+func New() *App {
+	return &App{Stdin: os.Stdin, Stdout: os.Stdout, Stderr: os.Stderr}
+}
+
+type command struct {
+	name    string
+	args    string
+	summary string
+	// setup registers the flags of the command and returns the function running it
+	setup func(a *App, fs *flag.FlagSet) func(args []string) error
+}
+
+func commands() []command {
+	return []command{
+		{"run", "", "fetch, decrypt, verify and submit a challenge", setupRun},
+		{"fetch", "", "fetch a new challenge into the answer file", setupFetch},
+		{"decrypt", "[text...]", "decrypt the answer file, or text from the arguments, -in or a pipe", setupDecrypt},
+		{"encrypt", "[text...]", "encrypt text from the arguments or the standard input", setupEncrypt},
+		{"crack", "[text...]", "rank every shift of a ciphertext by English letter frequencies or a language model", setupCrack},
+		{"affine", "[text...]", "encrypt, decrypt or brute-force the affine cipher E(x) = ax + b mod m", setupAffine},
+		{"transpose", "[text...]", "encrypt, decrypt or break rail fence and keyed columnar transpositions", setupTranspose},
+		{"solve", "[text...]", "break a monoalphabetic substitution by hill climbing over quadgram scores", setupSolve},
+		{"analyze", "[text...]", "report letter frequencies, index of coincidence, entropy, repeats and the likely cipher family", setupAnalyze},
+		{"train", "<corpus...>", "train a language model on corpus files for crack -lang", setupTrain},
+		{"batch", "", "decrypt or crack every item of a JSONL or CSV file concurrently", setupBatch},
+		{"watch", "<dir>", "decrypt files dropped into a directory and move them to done/ or failed/", setupWatch},
+		{"explore", "[text...]", "interactively step through every shift and save the chosen one", setupExplore},
+		{"verify", "", "recompute the answer and report the fields that differ", setupVerify},
+		{"submit", "", "verify and submit the answer file", setupSubmit},
+		{"history", "list | show <run> | resubmit [-force] <run>", "list, show and re-submit past runs", setupHistory},
+		{"serve", "", "serve the cipher over HTTP JSON endpoints and gRPC", setupServe},
+	}
+}
+
+// usageError is reported with ExitUsage and the usage of the command
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+func usagef(format string, args ...interface{}) error {
+	return &usageError{fmt.Sprintf(format, args...)}
+}
+
+// exitCode maps the error returned by a command to the exit code of the process
+func exitCode(err error) int {
+	var stepErr *runner.StepError
+	var usageErr *usageError
+
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.As(err, &usageErr):
+		return ExitUsage
+	case errors.Is(err, verify.ErrMismatch):
+		return ExitUnverified
+	case errors.As(err, &stepErr) && (stepErr.Step == runner.Fetch || stepErr.Step == runner.Submit):
+		return ExitRequest
+	}
+
+	return ExitError
+}
+
+func (a *App) usage() {
+	fmt.Fprintln(a.Stderr, "Usage: caesar <command> [flags] [arguments]")
+	fmt.Fprintln(a.Stderr)
+	fmt.Fprintln(a.Stderr, "Commands:")
+	for _, c := range commands() {
+		fmt.Fprintf(a.Stderr, "  %-10s %s\n", c.name, c.summary)
+	}
+
+	fmt.Fprintln(a.Stderr)
+	fmt.Fprintln(a.Stderr, "Run 'caesar help <command>' for the flags of a command.")
+	fmt.Fprintf(a.Stderr, "Exit codes: %d ok, %d error, %d usage, %d unverified answer, %d request failed\n",
+		ExitOK, ExitError, ExitUsage, ExitUnverified, ExitRequest)
+}
+
+func (a *App) flagSet(c command) (*flag.FlagSet, func(args []string) error) {
+	fs := flag.NewFlagSet(c.name, flag.ContinueOnError)
+	fs.SetOutput(a.Stderr)
+	run := c.setup(a, fs)
+	newLogger := logFlags(a, fs)
+	withMetrics := metricsFlags(a, fs)
+	withTracing := traceFlags(a, fs, c.name)
+	fs.Usage = func() {
+		fmt.Fprintf(a.Stderr, "Usage: caesar %s [flags] %s\n\n%s.\n\nFlags:\n", c.name, c.args, strings.ToUpper(c.summary[:1])+c.summary[1:])
+		fs.PrintDefaults()
+	}
+
+	return fs, func(args []string) error {
+		var err error
+		if a.logger, err = newLogger(); err != nil {
+			return err
+		}
+
+		return withTracing(func() error {
+			return withMetrics(func() error { return run(args) })
+		})
+	}
+}
+
+func lookup(name string) (command, bool) {
+	for _, c := range commands() {
+		if c.name == name {
+			return c, true
+		}
+	}
+
+	return command{}, false
+}
+
+// Run executes the command named by the first argument and returns the exit code
+func (a *App) Run(args []string) int {
+	if len(args) == 0 {
+		a.usage()
+		return ExitUsage
+	}
+
+	name, args := args[0], args[1:]
+	switch name {
+	case "help", "-h", "-help", "--help":
+		if len(args) == 0 {
+			a.usage()
+			return ExitOK
+		}
+
+		c, ok := lookup(args[0])
+		if !ok {
+			fmt.Fprintf(a.Stderr, "caesar: unknown command %q\n", args[0])
+			a.usage()
+			return ExitUsage
+		}
+
+		fs, _ := a.flagSet(c)
+		fs.Usage()
+		return ExitOK
+	}
+
+	c, ok := lookup(name)
+	if !ok {
+		fmt.Fprintf(a.Stderr, "caesar: unknown command %q\n", name)
+		a.usage()
+		return ExitUsage
+	}
+
+	fs, run := a.flagSet(c)
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return ExitOK
+		}
+
+		return ExitUsage
+	}
+
+	err := run(fs.Args())
+	if err != nil {
+		fmt.Fprintf(a.Stderr, "caesar %s: %v\n", c.name, err)
+		var usageErr *usageError
+		if errors.As(err, &usageErr) {
+			fs.Usage()
+		}
+	}
+
+	return exitCode(err)
+}
+
+// answerFlags registers the flags selecting the answer file and its format, returning the
+// file and a function resolving the format of an answer file, -format or its extension.
+// The format is passed down to the packages reading and writing answers instead of being
+// kept in config, so it does not outlive the command.
+func answerFlags(fs *flag.FlagSet) (*string, func(file string) (format.Format, error)) {
+	file := fs.String("answer", "./answer.json", "answer file")
+	name := fs.String("format", config.AnswerFormat, "answer file format, defaults to the file extension")
+	return file, func(file string) (format.Format, error) {
+		f, err := format.Resolve(*name, file)
+		if err != nil {
+			return nil, usagef("%v", err)
+		}
+
+		return f, nil
+	}
+}
+
+// historyFlags registers the flag selecting the history log, returning a function opening it
+func historyFlags(fs *flag.FlagSet) func() *history.Store {
+	path := config.HistoryFile
+	if path == "" {
+		path = "./history.jsonl"
+	}
+
+	file := fs.String("history", path, "JSON-lines log every run is recorded in, empty disables it")
+	return func() *history.Store {
+		if *file == "" {
+			return nil
+		}
+
+		return history.New(*file)
+	}
+}
+
+// logFlags registers the logging flags shared by every command, returning a function
+// building the logger they select
+func logFlags(a *App, fs *flag.FlagSet) func() (*slog.Logger, error) {
+	level := fs.String("log-level", config.LogLevel, "lowest level logged: debug, info, warn or error, defaults to info")
+	output := fs.String("log-format", config.LogFormat, "format of the log lines written to stderr: text or json, defaults to text")
+
+	return func() (*slog.Logger, error) {
+		l, err := logging.ParseLevel(*level)
+		if err != nil {
+			return nil, usagef("%v", err)
+		}
+
+		logger, err := logging.New(a.Stderr, l, *output)
+		if err != nil {
+			return nil, usagef("%v", err)
+		}
+
+		return logger, nil
+	}
+}
+
+// metricsFlags registers the metrics flags shared by every command, returning a function
+// running the command with /metrics served and writing the metrics file once it is done
+func metricsFlags(a *App, fs *flag.FlagSet) func(run func() error) error {
+	addr := fs.String("metrics-addr", config.MetricsAddr, "address /metrics is served on while the command runs, empty disables it")
+	file := fs.String("metrics-file", config.MetricsFile, "file the metrics are written to in the prometheus text format once the command is done")
+
+	return func(run func() error) error {
+		if *addr != "" {
+			l, err := net.Listen("tcp", *addr)
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			served := make(chan error, 1)
+			go func() { served <- metrics.Serve(ctx, l) }()
+			defer func() {
+				cancel()
+				<-served
+			}()
+
+			a.logger.Info("serving metrics", "addr", l.Addr().String())
+		}
+
+		err := run()
+		if *file != "" {
+			if writeErr := metrics.WriteFile(*file); err == nil {
+				err = writeErr
+			}
+		}
+
+		return err
+	}
+}
+
+// traceFlags registers the tracing flags shared by every command, returning a function
+// running the command in a span named after it and flushing the spans once it is done
+func traceFlags(a *App, fs *flag.FlagSet, name string) func(run func() error) error {
+	exporter := fs.String("trace", config.TraceExporter, "exporter of the spans: none, stdout (written to the standard error) or otlp, defaults to none")
+	endpoint := fs.String("trace-endpoint", config.TraceEndpoint, "URL of the OTLP collector, defaults to OTEL_EXPORTER_OTLP_ENDPOINT or http://localhost:4318")
+
+	return func(run func() error) error {
+		shutdown, err := tracing.Setup(context.Background(), *exporter, *endpoint, a.Stderr)
+		if err != nil {
+			return usagef("%v", err)
+		}
+
+		ctx, span := tracing.Start(context.Background(), "caesar "+name)
+		a.ctx = ctx
+		err = run()
+		tracing.End(span, err)
+
+		if shutdownErr := shutdown(context.Background()); shutdownErr != nil {
+			a.logger.Warn("flushing spans failed", "error", shutdownErr)
+		}
+
+		return err
+	}
+}
+
+// runContext returns the context of the running command carrying its logger tagged with the run ID
+func (a *App) runContext(runID string) context.Context {
+	return logging.NewContext(a.ctx, a.logger.With(logging.RunIDKey, runID))
+}
+
+// noArgs rejects positional arguments for commands that take none
+func noArgs(args []string) error {
+	if len(args) > 0 {
+		return usagef("unexpected arguments %q", args)
+	}
+
+	return nil
+}
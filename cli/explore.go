@@ -0,0 +1,475 @@
+package cli
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/term"
+
+	"github.com/wesleyholiveira/caesar-challenge/crypto"
+	"github.com/wesleyholiveira/caesar-challenge/model"
+	"github.com/wesleyholiveira/caesar-challenge/reader"
+	"github.com/wesleyholiveira/caesar-challenge/writer"
+)
+
+// commonWords is the dictionary used to highlight candidates when no -dict file is given
+var commonWords = strings.Fields(`
+a about after all also an and any are as at be because been but by can come could
+day do even first for from get give go good have he her him his how i if in into
+is it its just know like look make me most my new no not now of on one only or other
+our out over people say see she so some take than that the their them then there
+these they think this time to two up us use want was way we well what when which who
+will with work would year you your
+`)
+
+// Modes of the explore command, auto uses the terminal view when the standard input is a terminal
+const (
+	exploreAuto = "auto"
+	exploreTUI  = "tui"
+	exploreLine = "line"
+)
+
+const explorerHelp = "[+/-] step  [n] jump to shift  [b] best  [l] list  [s] save  [?] help  [q] quit"
+
+const terminalHelp = "←/→ step shift  ↑/↓ step rank  digits+enter jump  b best  s save  q quit"
+
+// ANSI sequences of the terminal view
+const (
+	ansiClear      = "\x1b[H\x1b[2J"
+	ansiEnterAlt   = "\x1b[?1049h\x1b[?25l"
+	ansiLeaveAlt   = "\x1b[?25h\x1b[?1049l"
+	ansiReverse    = "\x1b[7m"
+	ansiBold       = "\x1b[1m"
+	ansiHighlight  = "\x1b[1;32m"
+	ansiReset      = "\x1b[0m"
+	defaultColumns = 80
+)
+
+// explorer holds the state of an interactive exploration session
+type explorer struct {
+	app        *App
+	text       string
+	candidates []crypto.Candidate
+	places     int
+	dict       map[string]bool
+	color      bool
+}
+
+func setupExplore(a *App, fs *flag.FlagSet) func([]string) error {
+	file, answerFormat := answerFlags(fs)
+	in := fs.String("in", "", "explore this file instead of the answer file")
+	dict := fs.String("dict", "", "file with one dictionary word per line, defaults to common English words")
+	color := fs.Bool("color", true, "highlight dictionary words with ANSI colors")
+	save := fs.String("save", "", "answer file the chosen shift is saved to, defaults to -answer and is required with text")
+	ui := fs.String("ui", exploreAuto, "interface: tui for the full screen terminal view, line for a prompt reading commands, or auto")
+
+	return func(args []string) error {
+		if *ui != exploreAuto && *ui != exploreTUI && *ui != exploreLine {
+			return usagef("unknown interface %q, expected %s, %s or %s", *ui, exploreAuto, exploreTUI, exploreLine)
+		}
+
+		response := &model.ChallengeResponse{}
+		target := *save
+		if *in != "" || len(args) > 0 {
+			// free text has no token, saving it over the answer file would lose the challenge
+			if target == "" {
+				return usagef("-save is required when exploring text instead of the answer file")
+			}
+
+			text, err := a.readInput(*in, args)
+			if err != nil {
+				return err
+			}
+
+			response.CryptedText = text
+		} else {
+			f, err := answerFormat(*file)
+			if err != nil {
+				return err
+			}
+
+			if response, err = reader.ReadChallenge(*file, f); err != nil {
+				return err
+			}
+
+			if target == "" {
+				target = *file
+			}
+		}
+
+		f, err := answerFormat(target)
+		if err != nil {
+			return err
+		}
+
+		words := commonWords
+		if *dict != "" {
+			data, err := os.ReadFile(*dict)
+			if err != nil {
+				return err
+			}
+
+			words = strings.Fields(string(data))
+		}
+
+		e := &explorer{app: a, text: response.CryptedText, color: *color, dict: map[string]bool{}}
+		for _, w := range words {
+			e.dict[strings.ToLower(w)] = true
+		}
+
+		e.candidates = crypto.Crack(e.text)
+		e.places = e.candidates[0].Places
+
+		var places int
+		var ok bool
+		if *ui == exploreTUI || (*ui == exploreAuto && isTerminal(a.Stdin)) {
+			places, ok, err = e.terminal(a.Stdin, target)
+		} else {
+			places, ok, err = e.loop(a.Stdin)
+		}
+
+		if err != nil || !ok {
+			return err
+		}
+
+		response.Places = places
+		w := writer.New()
+		w.Logger = a.logger
+		w.File = target
+		w.Format = f
+		w.Response = response
+		if err := crypto.Decrypt(w); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(a.Stdout, "saved shift %d to %s\n", places, target)
+		return nil
+	}
+}
+
+// isTerminal reports whether r is a file attached to a terminal
+func isTerminal(r io.Reader) bool {
+	f, ok := r.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// loop reads commands until the user saves or quits, returning the chosen shift and whether to save it
+func (e *explorer) loop(in io.Reader) (int, bool, error) {
+	scanner := bufio.NewScanner(in)
+	e.show()
+
+	for {
+		fmt.Fprint(e.app.Stdout, "> ")
+		if !scanner.Scan() {
+			fmt.Fprintln(e.app.Stdout)
+			return 0, false, scanner.Err()
+		}
+
+		cmd := strings.TrimSpace(scanner.Text())
+		switch cmd {
+		case "+", "":
+			e.step(1)
+		case "-":
+			e.step(-1)
+		case "b":
+			e.places = e.candidates[0].Places
+		case "l":
+			e.list()
+			continue
+		case "s":
+			return e.places, true, nil
+		case "q":
+			return 0, false, nil
+		case "?", "h":
+			fmt.Fprintln(e.app.Stdout, explorerHelp)
+			continue
+		default:
+			n, err := strconv.Atoi(cmd)
+			if err != nil || n < 0 || n >= len(e.candidates) {
+				fmt.Fprintf(e.app.Stdout, "unknown command %q, %s\n", cmd, explorerHelp)
+				continue
+			}
+
+			e.places = n
+		}
+
+		e.show()
+	}
+}
+
+// terminal runs the full screen view, reading keys from in until the user saves or quits.
+// A terminal is switched to raw mode and the alternate screen for the session.
+func (e *explorer) terminal(in io.Reader, target string) (int, bool, error) {
+	columns := defaultColumns
+	if f, ok := in.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		state, err := term.MakeRaw(int(f.Fd()))
+		if err != nil {
+			return 0, false, err
+		}
+		defer term.Restore(int(f.Fd()), state)
+
+		if out, ok := e.app.Stdout.(*os.File); ok {
+			if width, _, err := term.GetSize(int(out.Fd())); err == nil && width > 0 {
+				columns = width
+			}
+		}
+	}
+
+	fmt.Fprint(e.app.Stdout, ansiEnterAlt)
+	defer fmt.Fprint(e.app.Stdout, ansiLeaveAlt)
+
+	keys := bufio.NewReader(in)
+	digits, status := "", ""
+	for {
+		e.render(columns, target, digits, status)
+		status = ""
+
+		key, err := readKey(keys)
+		if err == io.EOF {
+			return 0, false, nil
+		}
+
+		if err != nil {
+			return 0, false, err
+		}
+
+		switch key {
+		case keyRight, "+":
+			e.step(1)
+		case keyLeft, "-":
+			e.step(-1)
+		case keyUp:
+			e.stepRank(-1)
+		case keyDown:
+			e.stepRank(1)
+		case "b":
+			e.places = e.candidates[0].Places
+		case "s":
+			return e.places, true, nil
+		case "q", keyEscape, keyInterrupt:
+			return 0, false, nil
+		case keyBackspace:
+			if digits != "" {
+				digits = digits[:len(digits)-1]
+			}
+		case keyEnter:
+			if digits == "" {
+				continue
+			}
+
+			n, _ := strconv.Atoi(digits)
+			digits = ""
+			if n >= len(e.candidates) {
+				status = fmt.Sprintf("no shift %d, expected 0 to %d", n, len(e.candidates)-1)
+				continue
+			}
+
+			e.places = n
+		default:
+			if len(key) == 1 && key[0] >= '0' && key[0] <= '9' && len(digits) < 2 {
+				digits += key
+				continue
+			}
+
+			status = fmt.Sprintf("unknown key %q", key)
+		}
+	}
+}
+
+// Keys returned by readKey besides printable characters
+const (
+	keyUp        = "up"
+	keyDown      = "down"
+	keyRight     = "right"
+	keyLeft      = "left"
+	keyEnter     = "enter"
+	keyEscape    = "escape"
+	keyBackspace = "backspace"
+	keyInterrupt = "interrupt"
+)
+
+// readKey reads the next key pressed on a terminal in raw mode, decoding the escape sequences of the arrows
+func readKey(r *bufio.Reader) (string, error) {
+	char, _, err := r.ReadRune()
+	if err != nil {
+		return "", err
+	}
+
+	switch char {
+	case '\r', '\n':
+		return keyEnter, nil
+	case 0x7f, '\b':
+		return keyBackspace, nil
+	case 0x03:
+		return keyInterrupt, nil
+	case 0x1b:
+		// a lone escape has nothing buffered after it, an arrow is sent as ESC [ A-D at once
+		if r.Buffered() < 2 {
+			return keyEscape, nil
+		}
+
+		if next, _ := r.Peek(1); next[0] != '[' && next[0] != 'O' {
+			return keyEscape, nil
+		}
+
+		seq := make([]byte, 2)
+		if _, err := io.ReadFull(r, seq); err != nil {
+			return "", err
+		}
+
+		switch seq[1] {
+		case 'A':
+			return keyUp, nil
+		case 'B':
+			return keyDown, nil
+		case 'C':
+			return keyRight, nil
+		case 'D':
+			return keyLeft, nil
+		}
+
+		return "escape " + string(seq), nil
+	}
+
+	return string(char), nil
+}
+
+// step moves the current shift by delta, wrapping around
+func (e *explorer) step(delta int) {
+	n := len(e.candidates)
+	e.places = ((e.places+delta)%n + n) % n
+}
+
+// stepRank moves to the candidate delta places away in the ranking, stopping at both ends
+func (e *explorer) stepRank(delta int) {
+	_, rank := e.candidate(e.places)
+	i := rank - 1 + delta
+	if i < 0 || i >= len(e.candidates) {
+		return
+	}
+
+	e.places = e.candidates[i].Places
+}
+
+func (e *explorer) candidate(places int) (crypto.Candidate, int) {
+	for rank, c := range e.candidates {
+		if c.Places == places {
+			return c, rank + 1
+		}
+	}
+
+	return crypto.Candidate{}, 0
+}
+
+// render draws the whole terminal view: the ciphertext, the current shift with its plaintext,
+// every candidate ranked by score with the current one in reverse video, and the keys
+func (e *explorer) render(columns int, target, digits, status string) {
+	c, rank := e.candidate(e.places)
+	plain, found, total := e.highlight(c.Text)
+
+	lines := []string{
+		ansiBold + "caesar explore" + ansiReset + "  saving to " + target,
+		"",
+		"cipher: " + e.text,
+		"plain:  " + plain,
+		fmt.Sprintf("shift %2d  score %.2f  rank %d/%d  words %d/%d", c.Places, c.Score, rank, len(e.candidates), found, total),
+		"",
+	}
+
+	// the text of each row is cut to the width left by the columns before it
+	width := columns - len(" 26. shift 25  -9999.99  99/99  ")
+	for i, cand := range e.candidates {
+		text, found, total := e.highlight(truncate(cand.Text, width))
+		row := fmt.Sprintf(" %2d. shift %2d  %8.2f  %2d/%-2d  %s", i+1, cand.Places, cand.Score, found, total, text)
+		if cand.Places == e.places {
+			row = ansiReverse + row + ansiReset
+		}
+		lines = append(lines, row)
+	}
+
+	lines = append(lines, "", terminalHelp)
+	if digits != "" {
+		lines = append(lines, "shift: "+digits)
+	}
+	if status != "" {
+		lines = append(lines, status)
+	}
+
+	// raw mode does not turn a line feed into a carriage return and a line feed
+	fmt.Fprint(e.app.Stdout, ansiClear+strings.Join(lines, "\r\n")+"\r\n")
+}
+
+// truncate cuts text to at most width characters, ending with … when it is cut
+func truncate(text string, width int) string {
+	runes := []rune(text)
+	if width < 1 || len(runes) <= width {
+		return text
+	}
+
+	return string(runes[:width-1]) + "…"
+}
+
+// show prints the current shift with its score, rank and highlighted plaintext
+func (e *explorer) show() {
+	c, rank := e.candidate(e.places)
+	plain, found, total := e.highlight(c.Text)
+
+	fmt.Fprintf(e.app.Stdout, "shift %2d  score %.2f  rank %d/%d  words %d/%d\n", c.Places, c.Score, rank, len(e.candidates), found, total)
+	fmt.Fprintf(e.app.Stdout, "cipher: %s\n", e.text)
+	fmt.Fprintf(e.app.Stdout, "plain:  %s\n", plain)
+}
+
+// list prints every candidate ranked by score
+func (e *explorer) list() {
+	for rank, c := range e.candidates {
+		marker := " "
+		if c.Places == e.places {
+			marker = "*"
+		}
+
+		plain, _, _ := e.highlight(c.Text)
+		fmt.Fprintf(e.app.Stdout, "%s%2d. shift %2d  %8.2f  %s\n", marker, rank+1, c.Places, c.Score, plain)
+	}
+}
+
+// highlight marks the dictionary words of text, returning it with the number of words found and total
+func (e *explorer) highlight(text string) (string, int, int) {
+	var b strings.Builder
+	found, total := 0, 0
+
+	runes := []rune(text)
+	for i := 0; i < len(runes); {
+		if !unicode.IsLetter(runes[i]) {
+			b.WriteRune(runes[i])
+			i++
+			continue
+		}
+
+		j := i
+		for j < len(runes) && unicode.IsLetter(runes[j]) {
+			j++
+		}
+
+		word := string(runes[i:j])
+		total++
+		if e.dict[strings.ToLower(word)] {
+			found++
+			if e.color {
+				word = ansiHighlight + word + ansiReset
+			} else {
+				word = "[" + word + "]"
+			}
+		}
+
+		b.WriteString(word)
+		i = j
+	}
+
+	return b.String(), found, total
+}
@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/wesleyholiveira/caesar-challenge/format"
+	"github.com/wesleyholiveira/caesar-challenge/history"
+	"github.com/wesleyholiveira/caesar-challenge/request"
+	"github.com/wesleyholiveira/caesar-challenge/runner"
+	"github.com/wesleyholiveira/caesar-challenge/verify"
+)
+
+func setupHistory(a *App, fs *flag.FlagSet) func([]string) error {
+	store := historyFlags(fs)
+
+	return func(args []string) error {
+		if len(args) == 0 {
+			return usagef("missing action, expected list, show or resubmit")
+		}
+
+		s := store()
+		if s == nil {
+			return usagef("-history is required")
+		}
+
+		action, args := args[0], args[1:]
+		switch action {
+		case "list":
+			if err := noArgs(args); err != nil {
+				return err
+			}
+
+			return a.listRuns(s)
+		case "show":
+			if len(args) != 1 {
+				return usagef("show expects a run ID")
+			}
+
+			return a.showRun(s, args[0])
+		case "resubmit":
+			sub := flag.NewFlagSet("resubmit", flag.ContinueOnError)
+			sub.SetOutput(a.Stderr)
+			force := sub.Bool("force", false, "submit even when the answer does not verify")
+			if err := sub.Parse(args); err != nil {
+				return usagef("%v", err)
+			}
+
+			if sub.NArg() != 1 {
+				return usagef("resubmit expects a run ID")
+			}
+
+			return a.resubmitRun(s, sub.Arg(0), *force)
+		}
+
+		return usagef("unknown action %q, expected list, show or resubmit", action)
+	}
+}
+
+func (a *App) listRuns(s *history.Store) error {
+	runs, err := s.Runs()
+	if err != nil {
+		return err
+	}
+
+	for _, r := range runs {
+		status := "ok"
+		if r.Error != "" {
+			status = "failed"
+		}
+
+		cipher := ""
+		if r.Answer != nil {
+			cipher = r.Answer.CryptedText
+			if runes := []rune(cipher); len(runes) > 40 {
+				cipher = string(runes[:40]) + "..."
+			}
+		}
+
+		fmt.Fprintf(a.Stdout, "%s\t%s\t%-8s\t%s\t%s\n", r.ID, r.Started.Local().Format(time.RFC3339), r.LastStep(), status, cipher)
+	}
+
+	return nil
+}
+
+func (a *App) showRun(s *history.Store, id string) error {
+	r, err := s.Run(id)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.Stdout, "run %s\n", r.ID)
+	for _, e := range r.Events {
+		line := fmt.Sprintf("  %s  %s", e.Time.Local().Format(time.RFC3339), e.Step)
+		if e.Error != "" {
+			line += "  error: " + e.Error
+		}
+
+		fmt.Fprintln(a.Stdout, line)
+	}
+
+	if r.Answer != nil {
+		answer := *r.Answer
+		answer.Token = request.MaskToken(answer.Token, answer.Token)
+
+		data, err := format.PrettyJSON.Encode(answer)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(a.Stdout, "answer:\n%s", data)
+	}
+
+	if r.Response != "" {
+		fmt.Fprintf(a.Stdout, "response: %s\n", r.Response)
+	}
+
+	return nil
+}
+
+// resubmitRun submits the latest answer recorded for the run again and records the result
+func (a *App) resubmitRun(s *history.Store, id string, force bool) error {
+	r, err := s.Run(id)
+	if err != nil {
+		return err
+	}
+
+	if r.Answer == nil {
+		return fmt.Errorf("run %s has no answer to submit", r.ID)
+	}
+
+	if diffs := verify.Answer(r.Answer); len(diffs) > 0 {
+		report := &verify.Report{Answer: r.Answer, Diffs: diffs}
+		if !force {
+			return report.Err()
+		}
+
+		fmt.Fprintf(a.Stderr, "submitting unverified answer: %s\n", report)
+	}
+
+	respBody, err := request.PostAnswerContext(a.runContext(r.ID), r.Answer, a.PostRequest)
+	event := history.Event{RunID: r.ID, Step: string(runner.Submit), Answer: r.Answer, Response: string(respBody)}
+	if err != nil {
+		event.Error = err.Error()
+	}
+
+	if err := s.Append(event); err != nil {
+		return err
+	}
+
+	if err != nil {
+		return &runner.StepError{Step: runner.Submit, Err: err}
+	}
+
+	fmt.Fprintln(a.Stdout, string(respBody))
+	return nil
+}
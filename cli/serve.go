@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/wesleyholiveira/caesar-challenge/rpc"
+	"github.com/wesleyholiveira/caesar-challenge/server"
+)
+
+func setupServe(a *App, fs *flag.FlagSet) func([]string) error {
+	file, answerFormat := answerFlags(fs)
+	addr := fs.String("addr", ":8080", "address the HTTP endpoints listen on, empty disables them")
+	grpcAddr := fs.String("grpc-addr", "", "address the gRPC service listens on, empty disables it")
+	maxBody := fs.Int64("max-body", server.DefaultMaxBodyBytes, "largest request body accepted, in bytes")
+	timeout := fs.Duration("shutdown-timeout", server.DefaultShutdownTimeout, "time given to in-flight requests on shutdown")
+	store := historyFlags(fs)
+
+	return func(args []string) error {
+		if err := noArgs(args); err != nil {
+			return err
+		}
+
+		if *addr == "" && *grpcAddr == "" {
+			return usagef("at least one of -addr and -grpc-addr is required")
+		}
+
+		f, err := answerFormat(*file)
+		if err != nil {
+			return err
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		// the first server to fail stops the other one
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		errc := make(chan error, 2)
+		running := 0
+
+		if *addr != "" {
+			s := server.New()
+			s.MaxBodyBytes = *maxBody
+			s.ShutdownTimeout = *timeout
+
+			running++
+			fmt.Fprintf(a.Stderr, "http listening on %s\n", *addr)
+			go func() { errc <- s.ListenAndServe(ctx, *addr) }()
+		}
+
+		if *grpcAddr != "" {
+			l, err := net.Listen("tcp", *grpcAddr)
+			if err != nil {
+				return err
+			}
+
+			s := rpc.New(*file)
+			s.AnswerFormat = f
+			s.GetRequest = a.GetRequest
+			s.PostRequest = a.PostRequest
+			s.Logger = a.logger
+			s.History = store()
+
+			running++
+			fmt.Fprintf(a.Stderr, "grpc listening on %s\n", *grpcAddr)
+			go func() { errc <- s.Serve(ctx, l) }()
+		}
+
+		for ; running > 0; running-- {
+			if serveErr := <-errc; serveErr != nil && err == nil {
+				err = serveErr
+				cancel()
+			}
+		}
+
+		return err
+	}
+}
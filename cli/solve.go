@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/wesleyholiveira/caesar-challenge/crypto"
+	"github.com/wesleyholiveira/caesar-challenge/langmodel"
+)
+
+// cribFlags collects the repeated -crib flags, each one offset:text
+type cribFlags []crypto.Crib
+
+func (c *cribFlags) String() string {
+	cribs := make([]string, len(*c))
+	for i, crib := range *c {
+		cribs[i] = fmt.Sprintf("%d:%s", crib.Offset, crib.Text)
+	}
+
+	return strings.Join(cribs, ",")
+}
+
+func (c *cribFlags) Set(value string) error {
+	offset, text, ok := strings.Cut(value, ":")
+	if !ok {
+		return fmt.Errorf("expected offset:text, got %q", value)
+	}
+
+	n, err := strconv.Atoi(offset)
+	if err != nil {
+		return fmt.Errorf("invalid crib offset %q", offset)
+	}
+
+	*c = append(*c, crypto.Crib{Offset: n, Text: text})
+	return nil
+}
+
+func setupSolve(a *App, fs *flag.FlagSet) func([]string) error {
+	in := fs.String("in", "", "solve this file, or the standard input for -, instead of the arguments")
+	lang := fs.String("lang", langmodel.English, "language model scoring the quadgrams: en, pt or a file saved by train")
+	restarts := fs.Int("restarts", crypto.DefaultRestarts, "number of climbs from a random key")
+	steps := fs.Int("steps", crypto.DefaultSteps, "letter swaps tried by each climb")
+	temperature := fs.Float64("temperature", 20, "initial temperature of simulated annealing, 0 for plain hill climbing")
+	seed := fs.Int64("seed", 0, "seed of the random keys, 0 picks one from the clock")
+	var cribs cribFlags
+	fs.Var(&cribs, "crib", "known plaintext as offset:text, the offset counting letters of the ciphertext, may be repeated")
+
+	return func(args []string) error {
+		if *in == "" && len(args) == 0 {
+			return usagef("missing ciphertext")
+		}
+
+		text, err := a.readInput(*in, args)
+		if err != nil {
+			return err
+		}
+
+		model, err := langmodel.Lookup(*lang)
+		if err != nil {
+			return err
+		}
+
+		opts := crypto.SubstitutionOptions{
+			Scorer:      model.Quadgram,
+			Cribs:       cribs,
+			Restarts:    *restarts,
+			Steps:       *steps,
+			Temperature: *temperature,
+		}
+		if *seed != 0 {
+			opts.Rand = rand.New(rand.NewSource(*seed))
+		}
+
+		solution, err := crypto.SolveSubstitution(text, opts)
+		if err != nil {
+			return usagef("%v", err)
+		}
+
+		fmt.Fprintf(a.Stdout, "key\t%s\nscore\t%.2f\n%s\n", solution.Key, solution.Score, solution.Text)
+		return nil
+	}
+}
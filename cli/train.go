@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/wesleyholiveira/caesar-challenge/langmodel"
+	"github.com/wesleyholiveira/caesar-challenge/reader"
+)
+
+func setupTrain(a *App, fs *flag.FlagSet) func([]string) error {
+	out := fs.String("out", "model.json", "file the model is saved to")
+	name := fs.String("name", "", "name of the model, defaults to the -out file name without extension")
+
+	return func(args []string) error {
+		if len(args) == 0 {
+			return usagef("missing corpus file, - reads the standard input")
+		}
+
+		if *name == "" {
+			*name = strings.TrimSuffix(filepath.Base(*out), filepath.Ext(*out))
+		}
+
+		var corpora []io.Reader
+		for _, arg := range args {
+			if arg == reader.Stdin {
+				corpora = append(corpora, a.Stdin)
+				continue
+			}
+
+			f, err := os.Open(arg)
+			if err != nil {
+				return err
+			}
+
+			defer f.Close()
+			corpora = append(corpora, f)
+		}
+
+		model, err := langmodel.Train(*name, io.MultiReader(corpora...))
+		if err != nil {
+			return err
+		}
+
+		if err := model.SaveFile(*out); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(a.Stdout, "model %s saved to %s\n", model.Name, *out)
+		return nil
+	}
+}
@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/wesleyholiveira/caesar-challenge/crypto"
+	"github.com/wesleyholiveira/caesar-challenge/langmodel"
+	"github.com/wesleyholiveira/caesar-challenge/metrics"
+)
+
+func setupTranspose(a *App, fs *flag.FlagSet) func([]string) error {
+	in := fs.String("in", "", "read the text from this file, or the standard input for -, instead of the arguments")
+	rails := fs.Int("rails", 0, "number of rails of a rail fence")
+	key := fs.String("key", "", "keyword of a columnar transposition")
+	places := fs.Int("places", 0, "Caesar shift applied along with the transposition, in either order the result is the same")
+	decrypt := fs.Bool("decrypt", false, "decrypt the text instead of encrypting it")
+	crack := fs.Bool("crack", false, "rank every rail count and column order up to -max-rails and -max-columns")
+	caesar := fs.Bool("caesar", false, "with -crack, also crack a Caesar shift from the letter frequencies")
+	maxRails := fs.Int("max-rails", crypto.DefaultMaxRails, "largest rail count tried by -crack")
+	maxColumns := fs.Int("max-columns", crypto.DefaultMaxColumns, fmt.Sprintf("largest number of columns tried by -crack, every order is tried, at most %d", crypto.MaxColumnsLimit))
+	lang := fs.String("lang", langmodel.English, "language model ranking -crack candidates: en, pt or a file saved by train")
+	top := fs.Int("top", 5, "number of candidates printed by -crack, 0 prints all of them")
+
+	return func(args []string) error {
+		if *in == "" && len(args) == 0 {
+			return usagef("missing text")
+		}
+
+		text, err := a.readInput(*in, args)
+		if err != nil {
+			return err
+		}
+
+		if *crack {
+			if *maxColumns > crypto.MaxColumnsLimit {
+				return usagef("-max-columns must be at most %d", crypto.MaxColumnsLimit)
+			}
+
+			model, err := langmodel.Lookup(*lang)
+			if err != nil {
+				return err
+			}
+
+			candidates := crypto.CrackTransposition(text, crypto.TranspositionOptions{
+				Scorer:     model.Quadgram,
+				MaxRails:   *maxRails,
+				MaxColumns: *maxColumns,
+				Caesar:     *caesar,
+			})
+			metrics.ObserveCrack(len(candidates))
+			if *top > 0 && *top < len(candidates) {
+				candidates = candidates[:*top]
+			}
+
+			for _, c := range candidates {
+				k := c.Key
+				if c.Cipher == crypto.RailFence {
+					k = fmt.Sprint(c.Rails)
+				}
+				fmt.Fprintf(a.Stdout, "%s\t%s\t%2d\t%.2f\t%s\n", c.Cipher, k, c.Places, c.Score, c.Text)
+			}
+
+			return nil
+		}
+
+		if (*rails == 0) == (*key == "") {
+			return usagef("expected either -rails or -key")
+		}
+
+		var out string
+		switch {
+		case *rails != 0 && *decrypt:
+			out, err = crypto.DecryptRailFence(text, *rails)
+		case *rails != 0:
+			out, err = crypto.EncryptRailFence(text, *rails)
+		case *decrypt:
+			out, err = crypto.DecryptColumnar(text, *key)
+		default:
+			out, err = crypto.EncryptColumnar(text, *key)
+		}
+
+		if err != nil {
+			return usagef("%v", err)
+		}
+
+		if *places != 0 {
+			if *decrypt {
+				metrics.ObserveDecrypt(out)
+				out = crypto.DecryptText(out, *places)
+			} else {
+				out = crypto.EncryptText(out, *places)
+			}
+		}
+
+		fmt.Fprintln(a.Stdout, out)
+		return nil
+	}
+}
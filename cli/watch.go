@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/wesleyholiveira/caesar-challenge/config"
+	"github.com/wesleyholiveira/caesar-challenge/format"
+	"github.com/wesleyholiveira/caesar-challenge/watch"
+)
+
+func setupWatch(a *App, fs *flag.FlagSet) func([]string) error {
+	interval := fs.Duration("interval", watch.DefaultInterval, "time between scans of the directory")
+	settle := fs.Duration("settle", watch.DefaultSettle, "time a file must stay unmodified before it is processed")
+	places := fs.Int("places", 0, "shift every file is decrypted with, files are cracked when zero")
+	answerFormat := fs.String("format", config.AnswerFormat, "format of the answer files, defaults to json")
+	once := fs.Bool("once", false, "scan the directory a single time and exit")
+
+	return func(args []string) error {
+		if len(args) != 1 {
+			return usagef("watch expects a directory")
+		}
+
+		w := watch.New(args[0])
+		w.Interval = *interval
+		w.Settle = *settle
+		w.Places = *places
+		if *answerFormat != "" {
+			f, err := format.Lookup(*answerFormat)
+			if err != nil {
+				return usagef("%v", err)
+			}
+			w.Format = f
+		}
+
+		if err := w.Validate(); err != nil {
+			return usagef("%v", err)
+		}
+		w.Logger = a.logger
+		w.Report = func(r watch.Result) {
+			if r.Err != nil {
+				fmt.Fprintf(a.Stderr, "%s: %v\n", r.File, r.Err)
+				return
+			}
+
+			fmt.Fprintln(a.Stdout, r.Answer)
+		}
+
+		if *once {
+			results, err := w.Scan()
+			for _, r := range results {
+				w.Report(r)
+			}
+
+			return err
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		fmt.Fprintf(a.Stderr, "watching %s\n", args[0])
+		return w.Run(ctx)
+	}
+}
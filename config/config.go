@@ -1,8 +1,53 @@
 package config
 
-import "os"
+import (
+	"os"
+	"strconv"
+	"time"
+)
 
 var BaseUrl = os.Getenv("BASE_URL")
 var GenerateUrl = BaseUrl + "generate-data"
 var SubmitUrl = BaseUrl + "submit-solution"
 var TokenCodeNation = os.Getenv("TOKEN_CODENATION")
+var AnswerFormat = os.Getenv("ANSWER_FORMAT")
+var HistoryFile = os.Getenv("HISTORY_FILE")
+var MaxInputSize = parseSize(os.Getenv("MAX_INPUT_SIZE"))
+var LogLevel = os.Getenv("LOG_LEVEL")
+var LogFormat = os.Getenv("LOG_FORMAT")
+var MaxRetries = parseCount(os.Getenv("MAX_RETRIES"))
+var RetryDelay = parseDuration(os.Getenv("RETRY_DELAY"), time.Second)
+var MetricsAddr = os.Getenv("METRICS_ADDR")
+var MetricsFile = os.Getenv("METRICS_FILE")
+var TraceExporter = os.Getenv("TRACE_EXPORTER")
+var TraceEndpoint = os.Getenv("TRACE_ENDPOINT")
+
+// parseSize reads a byte count, an empty or invalid value means no limit
+func parseSize(s string) int64 {
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	return n
+}
+
+// parseCount reads a non negative count, an empty or invalid value means zero
+func parseCount(s string) int {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return 0
+	}
+
+	return n
+}
+
+// parseDuration reads a duration such as 500ms, an empty or invalid value means def
+func parseDuration(s string, def time.Duration) time.Duration {
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return def
+	}
+
+	return d
+}
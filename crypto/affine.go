@@ -0,0 +1,106 @@
+package crypto
+
+import (
+	"fmt"
+	"sort"
+)
+
+// AffineKey encrypts the character at index x of the alphabet as the one at (A*x + B) mod m,
+// m being the size of the alphabet. A must be coprime with m for the cipher to be reversible.
+type AffineKey struct {
+	A, B int
+	// Alphabet is the name or the characters of an alphabet given to LookupAlphabet, defaults to Latin
+	Alphabet string
+}
+
+// AffineCandidate is a possible decryption of an affine ciphertext
+type AffineCandidate struct {
+	Key  AffineKey
+	Text string
+	// Score is given to the text by the scorer ranking the candidates, higher is better
+	Score float64
+}
+
+// modInverse returns the inverse of a modulo m, or false when a is not coprime with m
+func modInverse(a, m int) (int, bool) {
+	a = (a%m + m) % m
+	t, newT, r, newR := 0, 1, m, a
+	for newR != 0 {
+		q := r / newR
+		t, newT = newT, t-q*newT
+		r, newR = newR, r-q*newR
+	}
+
+	if r != 1 {
+		return 0, false
+	}
+
+	return (t + m) % m, true
+}
+
+// EncryptAffine encrypts the characters of text found in the alphabet of key, keeping the others as is
+func EncryptAffine(text string, key AffineKey) (string, error) {
+	a, err := LookupAlphabet(key.Alphabet)
+	if err != nil {
+		return "", err
+	}
+
+	if _, ok := modInverse(key.A, a.Size()); !ok {
+		return "", fmt.Errorf("affine key a=%d is not coprime with the alphabet size %d", key.A, a.Size())
+	}
+
+	return a.transform(text, key.A, key.B, KeepUnknown)
+}
+
+// DecryptAffine reverses EncryptAffine, mapping index y back to a⁻¹(y - b) mod m
+func DecryptAffine(text string, key AffineKey) (string, error) {
+	a, err := LookupAlphabet(key.Alphabet)
+	if err != nil {
+		return "", err
+	}
+
+	inverse, ok := modInverse(key.A, a.Size())
+	if !ok {
+		return "", fmt.Errorf("affine key a=%d is not coprime with the alphabet size %d", key.A, a.Size())
+	}
+
+	return a.transform(text, inverse, -inverse*(key.B%a.Size()), KeepUnknown)
+}
+
+// CrackAffine decrypts text with every valid key of the alphabet and returns the candidates
+// ranked best first by English letter frequencies
+func CrackAffine(text, chars string) ([]AffineCandidate, error) {
+	return CrackAffineWith(text, chars, Score)
+}
+
+// CrackAffineWith decrypts text with every valid key of the alphabet and returns the candidates ranked best first by score
+func CrackAffineWith(text, chars string, score Scorer) ([]AffineCandidate, error) {
+	alphabet, err := LookupAlphabet(chars)
+	if err != nil {
+		return nil, err
+	}
+
+	m := alphabet.Size()
+	var candidates []AffineCandidate
+	for a := 1; a < m; a++ {
+		inverse, ok := modInverse(a, m)
+		if !ok {
+			continue
+		}
+
+		for b := 0; b < m; b++ {
+			plain, _ := alphabet.transform(text, inverse, -inverse*b, KeepUnknown)
+			candidates = append(candidates, AffineCandidate{
+				Key:   AffineKey{A: a, B: b, Alphabet: chars},
+				Text:  plain,
+				Score: score(plain),
+			})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+
+	return candidates, nil
+}
@@ -0,0 +1,219 @@
+package crypto
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Unknown is what a shift does with characters missing from its alphabet
+type Unknown int
+
+const (
+	// KeepUnknown copies them unchanged, as digits, spaces and punctuation of the challenge
+	KeepUnknown Unknown = iota
+	// DropUnknown leaves them out
+	DropUnknown
+	// RejectUnknown fails with ErrOutOfAlphabet
+	RejectUnknown
+)
+
+var unknownNames = []string{"keep", "drop", "error"}
+
+// ParseUnknown returns the handling named keep, drop or error
+func ParseUnknown(name string) (Unknown, error) {
+	for i, n := range unknownNames {
+		if strings.EqualFold(n, name) {
+			return Unknown(i), nil
+		}
+	}
+
+	return KeepUnknown, fmt.Errorf("unknown handling %q, expected one of %s", name, strings.Join(unknownNames, ", "))
+}
+
+func (u Unknown) String() string {
+	return unknownNames[u]
+}
+
+// LowerAlphabet holds the characters of the Latin alphabet of the challenge
+const LowerAlphabet = "abcdefghijklmnopqrstuvwxyz"
+
+// ErrInvalidAlphabet is returned for an alphabet with less than two characters or a repeated one
+var ErrInvalidAlphabet = errors.New("alphabet must hold at least two distinct characters")
+
+// ErrOutOfAlphabet is returned for a character missing from the alphabet when they are rejected
+var ErrOutOfAlphabet = errors.New("character is not in the alphabet")
+
+// Alphabet is an ordered set of characters that ciphers move text along, wrapping around at the end
+type Alphabet struct {
+	runes []rune
+	index map[rune]int
+	// caseless alphabets have no upper case letter, text is lowered before being looked up
+	caseless bool
+	// contiguous alphabets are a range of code points, looked up without the index
+	contiguous bool
+	// score ranks the shifts cracked along the alphabet, nil when its letter frequencies are unknown
+	score Scorer
+}
+
+// Alphabets known by name to LookupAlphabet
+var (
+	// Latin is the alphabet of the challenge, a-z
+	Latin = mustAlphabet(LowerAlphabet)
+	// Alphanumeric is a-z followed by 0-9
+	Alphanumeric = mustAlphabet(LowerAlphabet + "0123456789")
+	// Printable holds the 94 printable ASCII characters from ! to ~, a shift of 47 is ROT47
+	Printable = mustAlphabet(asciiRange('!', '~'))
+	// Cyrillic is the Russian alphabet, а-я with ё after е
+	Cyrillic = mustAlphabet("абвгдеёжзийклмнопрстуфхцчшщъыьэюя")
+	// Greek is α-ω, the final sigma ς is not part of it
+	Greek = mustAlphabet("αβγδεζηθικλμνξοπρστυφχψω")
+)
+
+func init() {
+	Latin.score = Score
+	Alphanumeric.score = Score
+	// printable shifts keep the case, a shift of 32 turns lower case letters into upper case ones
+	Printable.score = letterScorer(LowerAlphabet, englishFrequencies[:], 0.1)
+	Cyrillic.score = letterScorer(Cyrillic.String(), russianFrequencies[:], 1)
+	Greek.score = letterScorer(Greek.String(), greekFrequencies[:], 1)
+}
+
+var alphabets = map[string]*Alphabet{
+	"latin":        Latin,
+	"alphanumeric": Alphanumeric,
+	"printable":    Printable,
+	"cyrillic":     Cyrillic,
+	"greek":        Greek,
+}
+
+func asciiRange(first, last rune) string {
+	var b strings.Builder
+	for char := first; char <= last; char++ {
+		b.WriteRune(char)
+	}
+
+	return b.String()
+}
+
+func mustAlphabet(chars string) *Alphabet {
+	a, err := NewAlphabet(chars)
+	if err != nil {
+		panic(err)
+	}
+
+	return a
+}
+
+// NewAlphabet returns the alphabet of the distinct characters of chars, in order
+func NewAlphabet(chars string) (*Alphabet, error) {
+	runes := []rune(chars)
+	if len(runes) < 2 {
+		return nil, ErrInvalidAlphabet
+	}
+
+	a := &Alphabet{runes: runes, index: make(map[rune]int, len(runes)), caseless: true, contiguous: true}
+	for i, char := range runes {
+		if _, ok := a.index[char]; ok {
+			return nil, ErrInvalidAlphabet
+		}
+
+		a.index[char] = i
+		a.caseless = a.caseless && !unicode.IsUpper(char)
+		a.contiguous = a.contiguous && char == runes[0]+rune(i)
+	}
+
+	return a, nil
+}
+
+// LookupAlphabet returns the alphabet named latin, alphanumeric, printable, cyrillic or greek,
+// or else the alphabet of the characters of name. An empty name is Latin.
+func LookupAlphabet(name string) (*Alphabet, error) {
+	if name == "" {
+		return Latin, nil
+	}
+
+	if a, ok := alphabets[strings.ToLower(name)]; ok {
+		return a, nil
+	}
+
+	return NewAlphabet(name)
+}
+
+// Size returns the number of characters of the alphabet
+func (a *Alphabet) Size() int {
+	return len(a.runes)
+}
+
+// String returns the characters of the alphabet
+func (a *Alphabet) String() string {
+	return string(a.runes)
+}
+
+// Scorer returns the scorer cracking shifts along the alphabet from the letter frequencies of its
+// language, English for latin, alphanumeric and printable, or nil for alphabets of other characters
+func (a *Alphabet) Scorer() Scorer {
+	return a.score
+}
+
+// position returns the index of char in the alphabet, or false when it is missing
+func (a *Alphabet) position(char rune) (int, bool) {
+	if a.contiguous {
+		i := int(char - a.runes[0])
+		return i, i >= 0 && i < len(a.runes)
+	}
+
+	i, ok := a.index[char]
+	return i, ok
+}
+
+// transform moves every character of text found in the alphabet from index x to (mul*x + add) mod m,
+// handling the other characters as told by unknown
+func (a *Alphabet) transform(text string, mul, add int, unknown Unknown) (string, error) {
+	m := len(a.runes)
+	if a.caseless {
+		text = strings.ToLower(text)
+	}
+
+	var out strings.Builder
+	out.Grow(len(text))
+	for pos, char := range text {
+		if x, ok := a.position(char); ok {
+			out.WriteRune(a.runes[((mul*x+add)%m+m)%m])
+			continue
+		}
+
+		switch unknown {
+		case DropUnknown:
+			continue
+		case RejectUnknown:
+			return "", fmt.Errorf("%w: %q at byte %d", ErrOutOfAlphabet, char, pos)
+		}
+		out.WriteRune(char)
+	}
+
+	return out.String(), nil
+}
+
+// Encrypt shifts every character of text forward by places along the alphabet
+func (a *Alphabet) Encrypt(text string, places int, unknown Unknown) (string, error) {
+	return a.transform(text, 1, places%len(a.runes), unknown)
+}
+
+// Decrypt shifts every character of text back by places along the alphabet, the inverse of Encrypt
+func (a *Alphabet) Decrypt(text string, places int, unknown Unknown) (string, error) {
+	return a.transform(text, 1, -places%len(a.runes), unknown)
+}
+
+// CrackAlphabet decrypts text with every shift of the alphabet and returns the candidates ranked
+// best first by score, characters missing from the alphabet are kept
+func CrackAlphabet(text string, a *Alphabet, score Scorer) []Candidate {
+	candidates := make([]Candidate, 0, a.Size())
+	for places := 0; places < a.Size(); places++ {
+		plain, _ := a.transform(text, 1, -places, KeepUnknown)
+		candidates = append(candidates, Candidate{Places: places, Text: plain, Score: score(plain)})
+	}
+
+	return sortByScore(candidates)
+}
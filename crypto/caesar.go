@@ -1,32 +1,68 @@
 package crypto
 
 import (
+	"context"
 	"crypto/sha1"
 	"fmt"
 	"strings"
 
-	"github.com/wesleyholiveira/caesar-challenge/request"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/wesleyholiveira/caesar-challenge/logging"
+	"github.com/wesleyholiveira/caesar-challenge/model"
+	"github.com/wesleyholiveira/caesar-challenge/tracing"
 	"github.com/wesleyholiveira/caesar-challenge/writer"
 )
 
-func Decrypt(w *writer.WriterAnswer) {
-	r := w.Response.(*request.ChallengeResponse)
-	h := sha1.New()
-	places := r.Places
-	decryptedBytes := make([]byte, 0, len(r.CryptedText))
+// Range of shifts accepted by the challenge
+const (
+	MinPlaces = 1
+	MaxPlaces = 25
+)
+
+const alphabetSize = 26
+
+// Decrypt fills the decrypted text and summary of the challenge held by w and writes the answer
+func Decrypt(w *writer.WriterAnswer) error {
+	return DecryptContext(context.Background(), w)
+}
+
+// DecryptContext is Decrypt traced as a span of ctx, writing the answer in a child span
+func DecryptContext(ctx context.Context, w *writer.WriterAnswer) (err error) {
+	ctx, span := tracing.Start(ctx, "crypto.Decrypt")
+	defer func() { tracing.End(span, err) }()
+
+	r := w.Response.(*model.ChallengeResponse)
 	r.CryptedText = strings.ToLower(r.CryptedText)
+	r.Pipeline = ""
+	r.DecryptedText = DecryptText(r.CryptedText, r.Places)
+	r.SummaryCrypto = Summary(r.DecryptedText)
+	span.SetAttributes(attribute.Int("places", r.Places), attribute.Int("chars", len(r.CryptedText)))
+	logging.Or(w.Logger).Debug("challenge decrypted", "places", r.Places, "chars", len(r.CryptedText), "summary", r.SummaryCrypto)
 
-	for _, char := range r.CryptedText {
-		ascii := int(char)
-		if (char != ' ' && char != '.') && (ascii < 48 || ascii > 57) {
-			ascii -= places
-		}
-		decryptedBytes = append(decryptedBytes, byte(ascii))
-	}
+	return writer.WriteAnswerContext(ctx, w)
+}
+
+// EncryptText shifts every letter of text forward by places, the inverse of DecryptText
+func EncryptText(text string, places int) string {
+	return shift(text, -places)
+}
 
-	h.Write(decryptedBytes)
-	r.DecryptedText = string(decryptedBytes)
-	r.SummaryCrypto = fmt.Sprintf("%x", h.Sum(nil))
+// DecryptText shifts every letter of text back by places, wrapping around the alphabet.
+// The text is lowered first, digits, spaces, punctuation and other characters are kept as is.
+func DecryptText(text string, places int) string {
+	return shift(text, places)
+}
 
-	writer.WriteAnswer(w)
+// shift moves every letter of the lowered text back by places
+func shift(text string, places int) string {
+	out, _ := Latin.transform(text, 1, -places%alphabetSize, KeepUnknown)
+	return out
+}
+
+// Summary returns the hex encoded SHA-1 of text, the resumo_criptografico of the answer
+func Summary(text string) string {
+	h := sha1.New()
+	h.Write([]byte(text))
+	return fmt.Sprintf("%x", h.Sum(nil))
 }
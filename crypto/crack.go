@@ -0,0 +1,113 @@
+package crypto
+
+import (
+	"math"
+	"sort"
+	"unicode"
+)
+
+// englishFrequencies holds the relative frequency of each letter a-z in English text
+var englishFrequencies = [alphabetSize]float64{
+	0.08167, 0.01492, 0.02782, 0.04253, 0.12702, 0.02228, 0.02015,
+	0.06094, 0.06966, 0.00153, 0.00772, 0.04025, 0.02406, 0.06749,
+	0.07507, 0.01929, 0.00095, 0.05987, 0.06327, 0.09056, 0.02758,
+	0.00978, 0.02360, 0.00150, 0.01974, 0.00074,
+}
+
+// russianFrequencies holds the relative frequency of each letter of Cyrillic in Russian text
+var russianFrequencies = [...]float64{
+	0.08011, 0.01592, 0.04533, 0.01687, 0.02977, 0.08483, 0.00013, 0.00940, 0.01641, 0.07367, 0.01208,
+	0.03486, 0.04343, 0.03203, 0.06700, 0.10983, 0.02804, 0.04746, 0.05473, 0.06318, 0.02615, 0.00267,
+	0.00966, 0.00486, 0.01450, 0.00718, 0.00361, 0.00037, 0.01898, 0.01735, 0.00331, 0.00639, 0.02001,
+}
+
+// greekFrequencies holds the relative frequency of each letter of Greek in Greek text, the final
+// sigma counted as σ
+var greekFrequencies = [...]float64{
+	0.12000, 0.00800, 0.01900, 0.01800, 0.08000, 0.00400, 0.05200, 0.01400, 0.09000, 0.04300, 0.02900, 0.03400,
+	0.06500, 0.00400, 0.09800, 0.04300, 0.04400, 0.07800, 0.08300, 0.04200, 0.00800, 0.01200, 0.00200, 0.01900,
+}
+
+// Candidate is a possible decryption of a ciphertext
+type Candidate struct {
+	Places int
+	Text   string
+	// Score is given to the text by the scorer ranking the candidates, higher is better
+	Score float64
+}
+
+// Scorer returns how likely text is to be a plaintext, higher is better
+type Scorer func(text string) float64
+
+// otherFrequency is the likelihood given to characters that are neither letters nor spaces, below that
+// of an average letter so that shifts of larger alphabets turning letters into symbols rank lower
+const otherFrequency = 0.01
+
+// Score returns the log-likelihood of the letters of text under English letter frequencies,
+// other characters but spaces count as unlikely
+func Score(text string) float64 {
+	score := 0.0
+	for _, char := range text {
+		if char >= 'A' && char <= 'Z' {
+			char += 'a' - 'A'
+		}
+
+		switch {
+		case char >= 'a' && char <= 'z':
+			score += math.Log(englishFrequencies[char-'a'])
+		case !unicode.IsSpace(char):
+			score += math.Log(otherFrequency)
+		}
+	}
+
+	return score
+}
+
+// letterScorer returns the log-likelihood of text under the frequencies of the letters, in the same
+// order. Upper case letters count as their lower case one times upper, other characters but spaces
+// count as unlikely.
+func letterScorer(letters string, frequencies []float64, upper float64) Scorer {
+	logs := make(map[rune]float64, len(frequencies))
+	for i, letter := range []rune(letters) {
+		logs[letter] = math.Log(frequencies[i])
+	}
+
+	upperLog := math.Log(upper)
+	return func(text string) float64 {
+		score := 0.0
+		for _, char := range text {
+			lower := unicode.ToLower(char)
+			p, ok := logs[lower]
+			switch {
+			case ok && lower != char:
+				score += p + upperLog
+			case ok:
+				score += p
+			case !unicode.IsSpace(char):
+				score += math.Log(otherFrequency)
+			}
+		}
+
+		return score
+	}
+}
+
+// Crack decrypts text with every shift and returns the candidates ranked best first
+// by English letter frequencies
+func Crack(text string) []Candidate {
+	return CrackWith(text, Score)
+}
+
+// CrackWith decrypts text with every shift and returns the candidates ranked best first by score
+func CrackWith(text string, score Scorer) []Candidate {
+	return CrackAlphabet(text, Latin, score)
+}
+
+// sortByScore sorts the candidates best first, keeping the order of ties
+func sortByScore(candidates []Candidate) []Candidate {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+
+	return candidates
+}
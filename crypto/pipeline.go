@@ -0,0 +1,391 @@
+package crypto
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/wesleyholiveira/caesar-challenge/logging"
+	"github.com/wesleyholiveira/caesar-challenge/model"
+	"github.com/wesleyholiveira/caesar-challenge/tracing"
+	"github.com/wesleyholiveira/caesar-challenge/writer"
+)
+
+// Separators of a pipeline spec, stages are split by the first and a stage name from its argument by the second
+const (
+	StageSeparator = "|"
+	ArgSeparator   = ":"
+)
+
+// Stage is a reversible step of a pipeline
+type Stage interface {
+	Encrypt(text string) (string, error)
+	Decrypt(text string) (string, error)
+	// String returns the spec of the stage
+	String() string
+}
+
+// NewStage builds a stage from the argument following its name in a spec, empty when there is none
+type NewStage func(arg string) (Stage, error)
+
+var stages = map[string]NewStage{}
+
+// RegisterStage makes a stage available to pipeline specs under name
+func RegisterStage(name string, fn NewStage) {
+	stages[name] = fn
+}
+
+func init() {
+	RegisterStage("caesar", newCaesarStage)
+	RegisterStage("affine", newAffineStage)
+	RegisterStage("substitution", newSubstitutionStage)
+	RegisterStage("railfence", newRailFenceStage)
+	RegisterStage("columnar", newColumnarStage)
+	RegisterStage("reverse", noArg(reverseStage{}))
+	RegisterStage("base64", noArg(base64Stage{}))
+	RegisterStage("hex", noArg(hexStage{}))
+	RegisterStage("url", noArg(urlStage{}))
+}
+
+// StageNames returns the names of every registered stage
+func StageNames() []string {
+	names := make([]string, 0, len(stages))
+	for name := range stages {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// Pipeline applies its stages in order to encrypt, and undoes them in reverse order to decrypt
+type Pipeline []Stage
+
+// ParsePipeline builds the pipeline of a spec such as caesar:3|reverse|base64
+func ParsePipeline(spec string) (Pipeline, error) {
+	var p Pipeline
+	for _, part := range strings.Split(spec, StageSeparator) {
+		name, arg, _ := strings.Cut(strings.TrimSpace(part), ArgSeparator)
+		fn, ok := stages[strings.ToLower(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown pipeline stage %q, expected one of %s", name, strings.Join(StageNames(), ", "))
+		}
+
+		stage, err := fn(arg)
+		if err != nil {
+			return nil, fmt.Errorf("pipeline stage %q: %w", part, err)
+		}
+		p = append(p, stage)
+	}
+
+	return p, nil
+}
+
+// Encrypt runs text through every stage in order
+func (p Pipeline) Encrypt(text string) (string, error) {
+	for _, stage := range p {
+		var err error
+		if text, err = stage.Encrypt(text); err != nil {
+			return "", fmt.Errorf("%s: %w", stage, err)
+		}
+	}
+
+	return text, nil
+}
+
+// Decrypt undoes every stage, last first
+func (p Pipeline) Decrypt(text string) (string, error) {
+	for i := len(p) - 1; i >= 0; i-- {
+		var err error
+		if text, err = p[i].Decrypt(text); err != nil {
+			return "", fmt.Errorf("%s: %w", p[i], err)
+		}
+	}
+
+	return text, nil
+}
+
+// String returns the spec of the pipeline
+func (p Pipeline) String() string {
+	specs := make([]string, len(p))
+	for i, stage := range p {
+		specs[i] = stage.String()
+	}
+
+	return strings.Join(specs, StageSeparator)
+}
+
+// DecryptAnswer returns the decryption of the challenge lowered like DecryptText, by the pipeline
+// it records or else by its shift
+func DecryptAnswer(r *model.ChallengeResponse) (string, error) {
+	if r.Pipeline == "" {
+		return DecryptText(r.CryptedText, r.Places), nil
+	}
+
+	p, err := ParsePipeline(r.Pipeline)
+	if err != nil {
+		return "", err
+	}
+
+	text, err := p.Decrypt(r.CryptedText)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.ToLower(text), nil
+}
+
+// DecryptPipeline is Decrypt with the challenge decrypted by p instead of its shift, p is
+// recorded in the answer so that it can be verified
+func DecryptPipeline(w *writer.WriterAnswer, p Pipeline) error {
+	return DecryptPipelineContext(context.Background(), w, p)
+}
+
+// DecryptPipelineContext is DecryptPipeline traced as a span of ctx, writing the answer in a child span
+func DecryptPipelineContext(ctx context.Context, w *writer.WriterAnswer, p Pipeline) (err error) {
+	ctx, span := tracing.Start(ctx, "crypto.DecryptPipeline", attribute.String("pipeline", p.String()))
+	defer func() { tracing.End(span, err) }()
+
+	r := w.Response.(*model.ChallengeResponse)
+	text, err := p.Decrypt(r.CryptedText)
+	if err != nil {
+		return err
+	}
+
+	r.Pipeline = p.String()
+	r.DecryptedText = strings.ToLower(text)
+
+	r.SummaryCrypto = Summary(r.DecryptedText)
+	logging.Or(w.Logger).Debug("challenge decrypted", "pipeline", p.String(), "chars", len(r.CryptedText), "summary", r.SummaryCrypto)
+
+	return writer.WriteAnswerContext(ctx, w)
+}
+
+// noArg returns a constructor of stage rejecting any argument
+func noArg(stage Stage) NewStage {
+	return func(arg string) (Stage, error) {
+		if arg != "" {
+			return nil, fmt.Errorf("takes no argument, got %q", arg)
+		}
+
+		return stage, nil
+	}
+}
+
+// CaesarStage shifts characters along an alphabet, keeping those missing from it
+type CaesarStage struct {
+	Places int
+	// Alphabet is the name or the characters of an alphabet given to LookupAlphabet, defaults to Latin
+	Alphabet string
+	Unknown  Unknown
+}
+
+func newCaesarStage(arg string) (Stage, error) {
+	n, chars, _ := strings.Cut(arg, ",")
+	places, err := strconv.Atoi(n)
+	if err != nil {
+		return nil, fmt.Errorf("expected the shift as caesar:N or caesar:N,ALPHABET")
+	}
+
+	if _, err := LookupAlphabet(chars); err != nil {
+		return nil, err
+	}
+
+	return CaesarStage{Places: places, Alphabet: chars}, nil
+}
+
+func (s CaesarStage) Encrypt(text string) (string, error) {
+	a, err := LookupAlphabet(s.Alphabet)
+	if err != nil {
+		return "", err
+	}
+
+	return a.Encrypt(text, s.Places, s.Unknown)
+}
+
+func (s CaesarStage) Decrypt(text string) (string, error) {
+	a, err := LookupAlphabet(s.Alphabet)
+	if err != nil {
+		return "", err
+	}
+
+	return a.Decrypt(text, s.Places, s.Unknown)
+}
+
+func (s CaesarStage) String() string {
+	if s.Alphabet == "" {
+		return fmt.Sprintf("caesar:%d", s.Places)
+	}
+
+	return fmt.Sprintf("caesar:%d,%s", s.Places, s.Alphabet)
+}
+
+type affineStage AffineKey
+
+func newAffineStage(arg string) (Stage, error) {
+	a, b, ok := strings.Cut(arg, ",")
+	keyA, errA := strconv.Atoi(a)
+	keyB, errB := strconv.Atoi(b)
+	if !ok || errA != nil || errB != nil {
+		return nil, fmt.Errorf("expected the key as affine:A,B")
+	}
+
+	if _, ok := modInverse(keyA, alphabetSize); !ok {
+		return nil, fmt.Errorf("a=%d is not coprime with the alphabet size %d", keyA, alphabetSize)
+	}
+
+	return affineStage{A: keyA, B: keyB}, nil
+}
+
+func (s affineStage) Encrypt(text string) (string, error) {
+	return EncryptAffine(text, AffineKey(s))
+}
+
+func (s affineStage) Decrypt(text string) (string, error) {
+	return DecryptAffine(text, AffineKey(s))
+}
+
+func (s affineStage) String() string {
+	return fmt.Sprintf("affine:%d,%d", s.A, s.B)
+}
+
+type substitutionStage string
+
+func newSubstitutionStage(arg string) (Stage, error) {
+	if _, err := invertKey(arg); err != nil {
+		return nil, err
+	}
+
+	return substitutionStage(arg), nil
+}
+
+func (s substitutionStage) Encrypt(text string) (string, error) {
+	return EncryptSubstitution(text, string(s))
+}
+
+func (s substitutionStage) Decrypt(text string) (string, error) {
+	return DecryptSubstitution(text, string(s))
+}
+
+func (s substitutionStage) String() string {
+	return "substitution:" + string(s)
+}
+
+type railFenceStage int
+
+func newRailFenceStage(arg string) (Stage, error) {
+	rails, err := strconv.Atoi(arg)
+	if err != nil {
+		return nil, fmt.Errorf("expected the number of rails as railfence:N")
+	}
+
+	if rails < 2 {
+		return nil, ErrInvalidRails
+	}
+
+	return railFenceStage(rails), nil
+}
+
+func (s railFenceStage) Encrypt(text string) (string, error) {
+	return EncryptRailFence(text, int(s))
+}
+
+func (s railFenceStage) Decrypt(text string) (string, error) {
+	return DecryptRailFence(text, int(s))
+}
+
+func (s railFenceStage) String() string {
+	return fmt.Sprintf("railfence:%d", int(s))
+}
+
+type columnarStage string
+
+func newColumnarStage(arg string) (Stage, error) {
+	if arg == "" {
+		return nil, ErrEmptyColumnar
+	}
+
+	return columnarStage(arg), nil
+}
+
+func (s columnarStage) Encrypt(text string) (string, error) {
+	return EncryptColumnar(text, string(s))
+}
+
+func (s columnarStage) Decrypt(text string) (string, error) {
+	return DecryptColumnar(text, string(s))
+}
+
+func (s columnarStage) String() string {
+	return "columnar:" + string(s)
+}
+
+type reverseStage struct{}
+
+func (reverseStage) Encrypt(text string) (string, error) {
+	runes := []rune(text)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+
+	return string(runes), nil
+}
+
+func (s reverseStage) Decrypt(text string) (string, error) {
+	return s.Encrypt(text)
+}
+
+func (reverseStage) String() string {
+	return "reverse"
+}
+
+type base64Stage struct{}
+
+func (base64Stage) Encrypt(text string) (string, error) {
+	return base64.StdEncoding.EncodeToString([]byte(text)), nil
+}
+
+func (base64Stage) Decrypt(text string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(text))
+	return string(data), err
+}
+
+func (base64Stage) String() string {
+	return "base64"
+}
+
+type hexStage struct{}
+
+func (hexStage) Encrypt(text string) (string, error) {
+	return hex.EncodeToString([]byte(text)), nil
+}
+
+func (hexStage) Decrypt(text string) (string, error) {
+	data, err := hex.DecodeString(strings.TrimSpace(text))
+	return string(data), err
+}
+
+func (hexStage) String() string {
+	return "hex"
+}
+
+type urlStage struct{}
+
+func (urlStage) Encrypt(text string) (string, error) {
+	return url.QueryEscape(text), nil
+}
+
+func (urlStage) Decrypt(text string) (string, error) {
+	return url.QueryUnescape(text)
+}
+
+func (urlStage) String() string {
+	return "url"
+}
@@ -0,0 +1,252 @@
+package crypto
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/wesleyholiveira/caesar-challenge/langmodel"
+)
+
+// Defaults of the substitution solver
+const (
+	DefaultRestarts = 20
+	DefaultSteps    = 10000
+)
+
+// ErrInvalidKey is returned for a substitution key that is not a permutation of a-z
+var ErrInvalidKey = errors.New("substitution key must hold each letter a-z exactly once")
+
+// ErrUnscored is returned by SolveSubstitution when the scorer gives no key a finite score
+var ErrUnscored = errors.New("scorer gave no substitution key a finite score")
+
+// Crib is plaintext known to be found at Offset, counted in letters of the ciphertext
+type Crib struct {
+	Offset int
+	Text   string
+}
+
+// SubstitutionOptions tunes SolveSubstitution, zero values use the defaults
+type SubstitutionOptions struct {
+	// Scorer ranks the letters of candidate plaintexts, defaults to English quadgrams
+	Scorer Scorer
+	Cribs  []Crib
+	// Restarts is the number of climbs from a random key
+	Restarts int
+	// Steps is the number of letter swaps tried by each climb
+	Steps int
+	// Temperature is the initial temperature of simulated annealing, cooling to zero over
+	// the steps of a climb. Zero only accepts swaps that improve the score, as hill climbing.
+	Temperature float64
+	// Rand defaults to a source seeded with the current time
+	Rand *rand.Rand
+}
+
+// SubstitutionSolution is the best key found by SolveSubstitution
+type SubstitutionSolution struct {
+	// Key is the cipher alphabet, the letter each plaintext letter a-z is replaced by
+	Key   string
+	Text  string
+	Score float64
+}
+
+// SubstitutionKey returns the cipher alphabet of a Caesar shift by places
+func SubstitutionKey(places int) string {
+	return EncryptText("abcdefghijklmnopqrstuvwxyz", places)
+}
+
+// invertKey returns the plaintext letter of each cipher letter a-z
+func invertKey(key string) ([alphabetSize]byte, error) {
+	var inverse [alphabetSize]byte
+	key = strings.ToLower(key)
+	if len(key) != alphabetSize {
+		return inverse, ErrInvalidKey
+	}
+
+	for plain := 0; plain < alphabetSize; plain++ {
+		c := key[plain]
+		if c < 'a' || c > 'z' || inverse[c-'a'] != 0 {
+			return inverse, ErrInvalidKey
+		}
+		inverse[c-'a'] = 'a' + byte(plain)
+	}
+
+	return inverse, nil
+}
+
+// substitute replaces every letter of the lowered text by its entry in table
+func substitute(text string, table [alphabetSize]byte) string {
+	var b strings.Builder
+	b.Grow(len(text))
+	for _, char := range strings.ToLower(text) {
+		if char >= 'a' && char <= 'z' {
+			char = rune(table[char-'a'])
+		}
+		b.WriteRune(char)
+	}
+
+	return b.String()
+}
+
+// EncryptSubstitution replaces every letter of text by its entry in the cipher alphabet key
+func EncryptSubstitution(text, key string) (string, error) {
+	if _, err := invertKey(key); err != nil {
+		return "", err
+	}
+
+	var table [alphabetSize]byte
+	copy(table[:], strings.ToLower(key))
+	return substitute(text, table), nil
+}
+
+// DecryptSubstitution reverses EncryptSubstitution, the text is lowered and other characters are kept as is
+func DecryptSubstitution(text, key string) (string, error) {
+	inverse, err := invertKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	return substitute(text, inverse), nil
+}
+
+// SolveSubstitution searches the key of a monoalphabetic substitution by swapping the plaintext
+// letters of two cipher letters at a time and keeping the swaps that raise the score of the text
+func SolveSubstitution(text string, opts SubstitutionOptions) (SubstitutionSolution, error) {
+	if opts.Scorer == nil {
+		en, _ := langmodel.Builtin(langmodel.English)
+		opts.Scorer = en.Quadgram
+	}
+	if opts.Restarts <= 0 {
+		opts.Restarts = DefaultRestarts
+	}
+	if opts.Steps <= 0 {
+		opts.Steps = DefaultSteps
+	}
+	if opts.Rand == nil {
+		opts.Rand = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+
+	letters := lowerLetters(text)
+	fixed, err := cribConstraints(letters, opts.Cribs)
+	if err != nil {
+		return SubstitutionSolution{}, err
+	}
+
+	// free lists the cipher letters and plaintext letters left to the search
+	var free, freePlain []byte
+	taken := make(map[byte]bool)
+	for _, plain := range fixed {
+		if plain != 0 {
+			taken[plain] = true
+		}
+	}
+	for i := byte(0); i < alphabetSize; i++ {
+		if fixed[i] == 0 {
+			free = append(free, i)
+		}
+		if !taken['a'+i] {
+			freePlain = append(freePlain, 'a'+i)
+		}
+	}
+
+	decrypt := func(table [alphabetSize]byte) string {
+		out := make([]byte, len(letters))
+		for i := 0; i < len(letters); i++ {
+			out[i] = table[letters[i]-'a']
+		}
+
+		return string(out)
+	}
+
+	best := SubstitutionSolution{Score: math.Inf(-1)}
+	var bestTable [alphabetSize]byte
+	for restart := 0; restart < opts.Restarts; restart++ {
+		table := fixed
+		perm := opts.Rand.Perm(len(freePlain))
+		for i, c := range free {
+			table[c] = freePlain[perm[i]]
+		}
+
+		score := opts.Scorer(decrypt(table))
+		climbBest, climbTable := score, table
+		for step := 0; step < opts.Steps && len(free) > 1; step++ {
+			i, j := free[opts.Rand.Intn(len(free))], free[opts.Rand.Intn(len(free))]
+			if i == j {
+				continue
+			}
+
+			table[i], table[j] = table[j], table[i]
+			candidate := opts.Scorer(decrypt(table))
+
+			temperature := opts.Temperature * (1 - float64(step)/float64(opts.Steps))
+			if candidate >= score || (temperature > 0 && opts.Rand.Float64() < math.Exp((candidate-score)/temperature)) {
+				score = candidate
+				if score > climbBest {
+					climbBest, climbTable = score, table
+				}
+			} else {
+				table[i], table[j] = table[j], table[i]
+			}
+		}
+
+		if climbBest > best.Score {
+			best.Score, bestTable = climbBest, climbTable
+		}
+	}
+
+	// every score was -Inf or NaN, bestTable holds no key
+	if math.IsInf(best.Score, -1) {
+		return SubstitutionSolution{}, ErrUnscored
+	}
+
+	var key [alphabetSize]byte
+	for c, plain := range bestTable {
+		key[plain-'a'] = 'a' + byte(c)
+	}
+
+	best.Key = string(key[:])
+	best.Text = substitute(text, bestTable)
+	return best, nil
+}
+
+// lowerLetters returns the letters a-z of the lowered text
+func lowerLetters(text string) string {
+	var b strings.Builder
+	for _, char := range strings.ToLower(text) {
+		if char >= 'a' && char <= 'z' {
+			b.WriteRune(char)
+		}
+	}
+
+	return b.String()
+}
+
+// cribConstraints returns the plaintext letter each cipher letter is fixed to by the cribs, zero when free
+func cribConstraints(letters string, cribs []Crib) ([alphabetSize]byte, error) {
+	var fixed [alphabetSize]byte
+	for _, crib := range cribs {
+		plain := langmodel.Letters(crib.Text)
+		if crib.Offset < 0 || crib.Offset+len(plain) > len(letters) {
+			return fixed, fmt.Errorf("crib %q at %d is outside the %d letters of the ciphertext", crib.Text, crib.Offset, len(letters))
+		}
+
+		for i := 0; i < len(plain); i++ {
+			c := letters[crib.Offset+i] - 'a'
+			if fixed[c] != 0 && fixed[c] != plain[i] {
+				return fixed, fmt.Errorf("crib %q maps %c to %c, already mapped to %c", crib.Text, 'a'+c, plain[i], fixed[c])
+			}
+
+			for other, p := range fixed {
+				if p == plain[i] && byte(other) != c {
+					return fixed, fmt.Errorf("crib %q maps %c to %c, already the plaintext of %c", crib.Text, 'a'+c, plain[i], 'a'+other)
+				}
+			}
+			fixed[c] = plain[i]
+		}
+	}
+
+	return fixed, nil
+}
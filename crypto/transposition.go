@@ -0,0 +1,321 @@
+package crypto
+
+import (
+	"container/heap"
+	"errors"
+	"sort"
+
+	"github.com/wesleyholiveira/caesar-challenge/langmodel"
+)
+
+// Transposition ciphers reported by the breakers
+const (
+	RailFence = "railfence"
+	Columnar  = "columnar"
+)
+
+// Defaults of the transposition breakers
+const (
+	DefaultMaxRails   = 10
+	DefaultMaxColumns = 7
+	// MaxColumnsLimit caps MaxColumns, the orders tried grow with the factorial of the columns
+	MaxColumnsLimit = 9
+	// DefaultColumnarTop is the number of candidates CrackColumnar keeps
+	DefaultColumnarTop = 100
+)
+
+// Errors returned for invalid transposition keys
+var (
+	ErrInvalidRails  = errors.New("rail fence needs at least 2 rails")
+	ErrEmptyColumnar = errors.New("columnar key must not be empty")
+)
+
+// TranspositionOptions tunes the transposition breakers, zero values use the defaults
+type TranspositionOptions struct {
+	// Scorer ranks the candidates, defaults to English quadgrams
+	Scorer   Scorer
+	MaxRails int
+	// MaxColumns is at most MaxColumnsLimit, larger values are lowered to it
+	MaxColumns int
+	// Top is the number of best candidates CrackColumnar keeps out of every order it tries
+	Top int
+	// Caesar also undoes a Caesar shift. A shift replaces letters in place and a transposition moves
+	// them without replacing them, so either may have been applied first: the shift is cracked from
+	// the letter frequencies, which the transposition keeps, before the transposition is searched.
+	Caesar bool
+}
+
+// TranspositionCandidate is a possible decryption of a transposed ciphertext
+type TranspositionCandidate struct {
+	Cipher string
+	// Rails is the key of a rail fence, Key the keyword of a columnar transposition
+	Rails int
+	Key   string
+	// Places is the Caesar shift undone along with the transposition
+	Places int
+	Text   string
+	// Score is given to the text by the scorer ranking the candidates, higher is better
+	Score float64
+}
+
+// railPattern returns the rail of each position of a text of n characters zigzagging across rails
+func railPattern(n, rails int) []int {
+	pattern := make([]int, n)
+	rail, step := 0, 1
+	for i := range pattern {
+		pattern[i] = rail
+		if rails > 1 {
+			if rail == 0 {
+				step = 1
+			} else if rail == rails-1 {
+				step = -1
+			}
+			rail += step
+		}
+	}
+
+	return pattern
+}
+
+// railOrder returns the positions of the text in the order a rail fence reads them, rail by rail
+func railOrder(n, rails int) []int {
+	pattern := railPattern(n, rails)
+	order := make([]int, 0, n)
+	for rail := 0; rail < rails; rail++ {
+		for i, r := range pattern {
+			if r == rail {
+				order = append(order, i)
+			}
+		}
+	}
+
+	return order
+}
+
+// EncryptRailFence writes the characters of text in a zigzag across rails and reads them rail by rail
+func EncryptRailFence(text string, rails int) (string, error) {
+	if rails < 2 {
+		return "", ErrInvalidRails
+	}
+
+	runes := []rune(text)
+	out := make([]rune, len(runes))
+	for i, pos := range railOrder(len(runes), rails) {
+		out[i] = runes[pos]
+	}
+
+	return string(out), nil
+}
+
+// DecryptRailFence reverses EncryptRailFence
+func DecryptRailFence(text string, rails int) (string, error) {
+	if rails < 2 {
+		return "", ErrInvalidRails
+	}
+
+	return decryptRails([]rune(text), rails), nil
+}
+
+func decryptRails(runes []rune, rails int) string {
+	out := make([]rune, len(runes))
+	for i, pos := range railOrder(len(runes), rails) {
+		out[pos] = runes[i]
+	}
+
+	return string(out)
+}
+
+// columnOrder returns the columns in the order of the letters of key, ties in the order they appear
+func columnOrder(key string) []int {
+	runes := []rune(key)
+	order := make([]int, len(runes))
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		return runes[order[i]] < runes[order[j]]
+	})
+
+	return order
+}
+
+// columnarKey returns a keyword of letters a-z giving the column order
+func columnarKey(order []int) string {
+	key := make([]byte, len(order))
+	for rank, col := range order {
+		key[col] = 'a' + byte(rank)
+	}
+
+	return string(key)
+}
+
+// EncryptColumnar writes the characters of text in rows as wide as key and reads the columns
+// in the alphabetical order of the letters of key. The last row is not padded.
+func EncryptColumnar(text, key string) (string, error) {
+	if key == "" {
+		return "", ErrEmptyColumnar
+	}
+
+	runes := []rune(text)
+	order := columnOrder(key)
+	out := make([]rune, 0, len(runes))
+	for _, col := range order {
+		for i := col; i < len(runes); i += len(order) {
+			out = append(out, runes[i])
+		}
+	}
+
+	return string(out), nil
+}
+
+// DecryptColumnar reverses EncryptColumnar
+func DecryptColumnar(text, key string) (string, error) {
+	if key == "" {
+		return "", ErrEmptyColumnar
+	}
+
+	return decryptColumns([]rune(text), columnOrder(key)), nil
+}
+
+func decryptColumns(runes []rune, order []int) string {
+	out := make([]rune, len(runes))
+	next := 0
+	for _, col := range order {
+		for i := col; i < len(runes); i += len(order) {
+			out[i] = runes[next]
+			next++
+		}
+	}
+
+	return string(out)
+}
+
+// permutations calls fn with every ordering of 0..n-1, reusing the same slice
+func permutations(n int, fn func([]int)) {
+	p := make([]int, n)
+	for i := range p {
+		p[i] = i
+	}
+
+	var permute func(k int)
+	permute = func(k int) {
+		if k == n {
+			fn(p)
+			return
+		}
+
+		for i := k; i < n; i++ {
+			p[k], p[i] = p[i], p[k]
+			permute(k + 1)
+			p[k], p[i] = p[i], p[k]
+		}
+	}
+	permute(0)
+}
+
+// withDefaults fills the zero options and undoes the Caesar shift of text when asked to
+func (opts *TranspositionOptions) withDefaults(text string) (string, int) {
+	if opts.Scorer == nil {
+		en, _ := langmodel.Builtin(langmodel.English)
+		opts.Scorer = en.Quadgram
+	}
+	if opts.MaxRails <= 0 {
+		opts.MaxRails = DefaultMaxRails
+	}
+	if opts.MaxColumns <= 0 {
+		opts.MaxColumns = DefaultMaxColumns
+	}
+	if opts.MaxColumns > MaxColumnsLimit {
+		opts.MaxColumns = MaxColumnsLimit
+	}
+	if opts.Top <= 0 {
+		opts.Top = DefaultColumnarTop
+	}
+
+	if !opts.Caesar {
+		return text, 0
+	}
+
+	places := Crack(text)[0].Places
+	return shift(text, places), places
+}
+
+// CrackRailFence decrypts text with every rail count up to opts.MaxRails and returns the candidates ranked best first
+func CrackRailFence(text string, opts TranspositionOptions) []TranspositionCandidate {
+	text, places := opts.withDefaults(text)
+	runes := []rune(text)
+
+	var candidates []TranspositionCandidate
+	for rails := 2; rails <= opts.MaxRails; rails++ {
+		plain := decryptRails(runes, rails)
+		candidates = append(candidates, TranspositionCandidate{
+			Cipher: RailFence,
+			Rails:  rails,
+			Places: places,
+			Text:   plain,
+			Score:  opts.Scorer(plain),
+		})
+	}
+
+	return sortCandidates(candidates)
+}
+
+// CrackColumnar decrypts text with every column order of up to opts.MaxColumns columns and
+// returns the opts.Top best candidates ranked best first
+func CrackColumnar(text string, opts TranspositionOptions) []TranspositionCandidate {
+	text, places := opts.withDefaults(text)
+	runes := []rune(text)
+
+	best := &candidateHeap{}
+	for n := 2; n <= opts.MaxColumns && n <= len(runes); n++ {
+		permutations(n, func(order []int) {
+			plain := decryptColumns(runes, order)
+			score := opts.Scorer(plain)
+			if best.Len() == opts.Top && score <= (*best)[0].Score {
+				return
+			}
+
+			heap.Push(best, TranspositionCandidate{
+				Cipher: Columnar,
+				Key:    columnarKey(order),
+				Places: places,
+				Text:   plain,
+				Score:  score,
+			})
+			if best.Len() > opts.Top {
+				heap.Pop(best)
+			}
+		})
+	}
+
+	return sortCandidates(*best)
+}
+
+// CrackTransposition ranks together the candidates of the rail fence and columnar breakers
+func CrackTransposition(text string, opts TranspositionOptions) []TranspositionCandidate {
+	return sortCandidates(append(CrackRailFence(text, opts), CrackColumnar(text, opts)...))
+}
+
+func sortCandidates(candidates []TranspositionCandidate) []TranspositionCandidate {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+
+	return candidates
+}
+
+// candidateHeap is a min-heap of candidates by score, its root is the worst one kept
+type candidateHeap []TranspositionCandidate
+
+func (h candidateHeap) Len() int            { return len(h) }
+func (h candidateHeap) Less(i, j int) bool  { return h[i].Score < h[j].Score }
+func (h candidateHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *candidateHeap) Push(x interface{}) { *h = append(*h, x.(TranspositionCandidate)) }
+
+func (h *candidateHeap) Pop() interface{} {
+	old := *h
+	c := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return c
+}
@@ -0,0 +1,227 @@
+package format
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+type csvFormat struct{}
+
+// CSV encodes a struct, or a slice of structs, as a header row followed by one row per value.
+// Columns are named after the json tags of the struct fields.
+var CSV Format = csvFormat{}
+
+func (csvFormat) Name() string {
+	return "csv"
+}
+
+func (csvFormat) Extensions() []string {
+	return []string{".csv"}
+}
+
+func (csvFormat) Encode(v interface{}) ([]byte, error) {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+
+	var rows []reflect.Value
+	switch rv.Kind() {
+	case reflect.Struct:
+		rows = []reflect.Value{rv}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			rows = append(rows, reflect.Indirect(rv.Index(i)))
+		}
+	default:
+		return nil, fmt.Errorf("csv: cannot encode %T, expected a struct or a slice of structs", v)
+	}
+
+	elem := rv.Type()
+	if rv.Kind() != reflect.Struct {
+		elem = elem.Elem()
+		if elem.Kind() == reflect.Ptr {
+			elem = elem.Elem()
+		}
+	}
+
+	if elem.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("csv: cannot encode %T, expected a struct or a slice of structs", v)
+	}
+
+	columns := csvColumns(elem)
+	buf := new(bytes.Buffer)
+	w := csv.NewWriter(buf)
+
+	header := make([]string, len(columns))
+	for i, c := range columns {
+		header[i] = c.name
+	}
+
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		record := make([]string, len(columns))
+		for i, c := range columns {
+			record[i] = fmt.Sprint(row.Field(c.index).Interface())
+		}
+
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+func (csvFormat) Decode(data []byte, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("csv: cannot decode into %T, expected a pointer", v)
+	}
+
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return err
+	}
+
+	if len(records) == 0 {
+		return fmt.Errorf("csv: missing header row")
+	}
+
+	header, rows := records[0], records[1:]
+	target := rv.Elem()
+
+	switch target.Kind() {
+	case reflect.Struct:
+		if len(rows) == 0 {
+			return fmt.Errorf("csv: no data rows")
+		}
+
+		return csvDecodeRow(header, rows[0], target)
+	case reflect.Slice:
+		elem := target.Type().Elem()
+		ptr := elem.Kind() == reflect.Ptr
+		if ptr {
+			elem = elem.Elem()
+		}
+
+		if elem.Kind() != reflect.Struct {
+			return fmt.Errorf("csv: cannot decode into %T, expected a struct or a slice of structs", v)
+		}
+
+		slice := reflect.MakeSlice(target.Type(), 0, len(rows))
+		for _, row := range rows {
+			item := reflect.New(elem)
+			if err := csvDecodeRow(header, row, item.Elem()); err != nil {
+				return err
+			}
+
+			if !ptr {
+				item = item.Elem()
+			}
+
+			slice = reflect.Append(slice, item)
+		}
+
+		target.Set(slice)
+		return nil
+	}
+
+	return fmt.Errorf("csv: cannot decode into %T, expected a struct or a slice of structs", v)
+}
+
+type csvColumn struct {
+	name  string
+	index int
+}
+
+func csvColumns(t reflect.Type) []csvColumn {
+	columns := make([]csvColumn, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		columns = append(columns, csvColumn{name: name, index: i})
+	}
+
+	return columns
+}
+
+func csvDecodeRow(header, row []string, target reflect.Value) error {
+	if len(row) != len(header) {
+		return fmt.Errorf("csv: row has %d fields, header has %d", len(row), len(header))
+	}
+
+	index := map[string]int{}
+	for _, c := range csvColumns(target.Type()) {
+		index[c.name] = c.index
+	}
+
+	for i, name := range header {
+		fi, ok := index[name]
+		if !ok {
+			continue
+		}
+
+		if err := csvSetField(target.Field(fi), row[i]); err != nil {
+			return fmt.Errorf("csv: column %s: %v", name, err)
+		}
+	}
+
+	return nil
+}
+
+func csvSetField(field reflect.Value, value string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		field.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(value, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		field.SetFloat(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+
+		field.SetBool(b)
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package format
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Encoder serializes a value into bytes
+type Encoder interface {
+	Encode(v interface{}) ([]byte, error)
+}
+
+// Decoder deserializes bytes into the value pointed to by v
+type Decoder interface {
+	Decode(data []byte, v interface{}) error
+}
+
+// Format is a named serialization format bound to a set of file extensions
+type Format interface {
+	Encoder
+	Decoder
+	Name() string
+	Extensions() []string
+}
+
+var formats = map[string]Format{}
+
+// Register makes a format available to Lookup and ForFile
+func Register(f Format) {
+	formats[f.Name()] = f
+}
+
+func init() {
+	Register(JSON)
+	Register(PrettyJSON)
+	Register(YAML)
+	Register(TOML)
+	Register(CSV)
+}
+
+// Names returns the names of every registered format
+func Names() []string {
+	names := make([]string, 0, len(formats))
+	for name := range formats {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// Lookup returns the format registered under name
+func Lookup(name string) (Format, error) {
+	f, ok := formats[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown format %q, expected one of %s", name, strings.Join(Names(), ", "))
+	}
+
+	return f, nil
+}
+
+// ForFile picks the format from the file extension, ignoring a trailing .gz, falling back to JSON
+func ForFile(file string) Format {
+	ext := strings.ToLower(filepath.Ext(strings.TrimSuffix(strings.ToLower(file), ".gz")))
+	if ext == "" {
+		return JSON
+	}
+
+	for _, name := range Names() {
+		for _, e := range formats[name].Extensions() {
+			if e == ext {
+				return formats[name]
+			}
+		}
+	}
+
+	return JSON
+}
+
+// Resolve returns the format called name or, when name is empty, the one matching file
+func Resolve(name, file string) (Format, error) {
+	if name != "" {
+		return Lookup(name)
+	}
+
+	return ForFile(file), nil
+}
@@ -0,0 +1,46 @@
+package format
+
+import "encoding/json"
+
+type jsonFormat struct {
+	indent string
+}
+
+// JSON is the compact JSON format expected by the challenge API
+var JSON Format = jsonFormat{}
+
+// PrettyJSON is JSON indented for humans, selected by name only
+var PrettyJSON Format = jsonFormat{indent: "  "}
+
+func (f jsonFormat) Name() string {
+	if f.indent != "" {
+		return "json-pretty"
+	}
+
+	return "json"
+}
+
+func (f jsonFormat) Extensions() []string {
+	if f.indent != "" {
+		return nil
+	}
+
+	return []string{".json"}
+}
+
+func (f jsonFormat) Encode(v interface{}) ([]byte, error) {
+	if f.indent != "" {
+		data, err := json.MarshalIndent(v, "", f.indent)
+		if err != nil {
+			return nil, err
+		}
+
+		return append(data, '\n'), nil
+	}
+
+	return json.Marshal(v)
+}
+
+func (f jsonFormat) Decode(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
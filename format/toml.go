@@ -0,0 +1,34 @@
+package format
+
+import (
+	"bytes"
+
+	"github.com/BurntSushi/toml"
+)
+
+type tomlFormat struct{}
+
+// TOML encodes values as a TOML document, the value must be a struct or a map
+var TOML Format = tomlFormat{}
+
+func (tomlFormat) Name() string {
+	return "toml"
+}
+
+func (tomlFormat) Extensions() []string {
+	return []string{".toml"}
+}
+
+func (tomlFormat) Encode(v interface{}) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	if err := toml.NewEncoder(buf).Encode(v); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (tomlFormat) Decode(data []byte, v interface{}) error {
+	_, err := toml.Decode(string(data), v)
+	return err
+}
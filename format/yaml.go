@@ -0,0 +1,24 @@
+package format
+
+import "gopkg.in/yaml.v3"
+
+type yamlFormat struct{}
+
+// YAML encodes values as a YAML document
+var YAML Format = yamlFormat{}
+
+func (yamlFormat) Name() string {
+	return "yaml"
+}
+
+func (yamlFormat) Extensions() []string {
+	return []string{".yaml", ".yml"}
+}
+
+func (yamlFormat) Encode(v interface{}) ([]byte, error) {
+	return yaml.Marshal(v)
+}
+
+func (yamlFormat) Decode(data []byte, v interface{}) error {
+	return yaml.Unmarshal(data, v)
+}
@@ -0,0 +1,200 @@
+package history
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wesleyholiveira/caesar-challenge/model"
+)
+
+// ErrNotFound is returned when no run matches the requested ID
+var ErrNotFound = errors.New("run not found")
+
+// Event is a single line of the history log, recording a completed step of a run
+type Event struct {
+	RunID  string                   `json:"run_id"`
+	Time   time.Time                `json:"time"`
+	Step   string                   `json:"step"`
+	Answer *model.ChallengeResponse `json:"answer,omitempty"`
+	// Response is the body returned by submit-solution
+	Response string `json:"response,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Run gathers the events of a run, its answer and response are the latest recorded
+type Run struct {
+	ID       string
+	Started  time.Time
+	Updated  time.Time
+	Events   []Event
+	Answer   *model.ChallengeResponse
+	Response string
+	Error    string
+}
+
+// LastStep returns the step of the latest event of the run
+func (r *Run) LastStep() string {
+	return r.Events[len(r.Events)-1].Step
+}
+
+// Store is an append-only JSON-lines log of run events
+type Store struct {
+	Path string
+	mu   sync.Mutex
+}
+
+// New returns a Store backed by the file at path, created on the first Append
+func New(path string) *Store {
+	return &Store{Path: path}
+}
+
+// NewRunID returns a unique run ID that sorts by start time
+func NewRunID() string {
+	b := make([]byte, 4)
+	rand.Read(b)
+	return time.Now().UTC().Format("20060102T150405") + "-" + hex.EncodeToString(b)
+}
+
+// Append writes the event to the log and syncs it to disk, a copy of the answer is stored
+// so later changes to it do not alter the event
+func (s *Store) Append(e Event) error {
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+
+	if e.Answer != nil {
+		answer := *e.Answer
+		e.Answer = &answer
+	}
+
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// Events reads every event of the log in the order they were appended
+func (s *Store) Events() ([]Event, error) {
+	f, err := os.Open(s.Path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	events := []Event{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+
+		e := Event{}
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", s.Path, n, err)
+		}
+
+		events = append(events, e)
+	}
+
+	return events, scanner.Err()
+}
+
+// Runs groups the events of the log by run, oldest first
+func (s *Store) Runs() ([]*Run, error) {
+	events, err := s.Events()
+	if err != nil {
+		return nil, err
+	}
+
+	byID := map[string]*Run{}
+	runs := []*Run{}
+	for _, e := range events {
+		r, ok := byID[e.RunID]
+		if !ok {
+			r = &Run{ID: e.RunID, Started: e.Time}
+			byID[e.RunID] = r
+			runs = append(runs, r)
+		}
+
+		r.Events = append(r.Events, e)
+		r.Updated = e.Time
+		r.Error = e.Error
+		if e.Answer != nil {
+			r.Answer = e.Answer
+		}
+
+		if e.Response != "" {
+			r.Response = e.Response
+		}
+	}
+
+	sort.SliceStable(runs, func(i, j int) bool {
+		return runs[i].Started.Before(runs[j].Started)
+	})
+
+	return runs, nil
+}
+
+// Run returns the run whose ID is id or starts with it, failing when the prefix is ambiguous
+func (s *Store) Run(id string) (*Run, error) {
+	runs, err := s.Runs()
+	if err != nil {
+		return nil, err
+	}
+
+	var found *Run
+	for _, r := range runs {
+		if r.ID == id {
+			return r, nil
+		}
+
+		if id != "" && strings.HasPrefix(r.ID, id) {
+			if found != nil {
+				return nil, fmt.Errorf("run ID %q is ambiguous", id)
+			}
+
+			found = r
+		}
+	}
+
+	if found == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+
+	return found, nil
+}
@@ -0,0 +1,78 @@
+package langmodel
+
+import (
+	"compress/gzip"
+	"embed"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Names of the built-in models
+const (
+	English    = "en"
+	Portuguese = "pt"
+)
+
+// corpora holds the gzipped training text of each built-in model. The English one is made of
+// two public domain books, The Adventures of Tom Sawyer by Mark Twain and Opticks by Isaac
+// Newton, as published by Project Gutenberg: close to a million characters, enough to see most
+// of the quadgrams of the language. The Portuguese one is about seven hundred thousand
+// characters of stories and expository prose written for the project, of the same order.
+//
+//go:embed corpus/*.txt.gz
+var corpora embed.FS
+
+var (
+	builtinOnce   = map[string]*sync.Once{English: {}, Portuguese: {}}
+	builtinModels = map[string]*Model{}
+)
+
+// Builtin returns the model trained on the corpus embedded for the language, en or pt
+func Builtin(name string) (*Model, bool) {
+	once, ok := builtinOnce[name]
+	if !ok {
+		return nil, false
+	}
+
+	once.Do(func() {
+		f, err := corpora.Open("corpus/" + name + ".txt.gz")
+		if err != nil {
+			panic(err)
+		}
+		defer f.Close()
+
+		r, err := gzip.NewReader(f)
+		if err != nil {
+			panic(err)
+		}
+
+		m, err := Train(name, r)
+		if err != nil {
+			panic(err)
+		}
+		builtinModels[name] = m
+	})
+
+	return builtinModels[name], true
+}
+
+// Lookup returns the built-in model for the language name, or loads the model saved in the file name
+func Lookup(name string) (*Model, error) {
+	switch strings.ToLower(name) {
+	case English, "english":
+		name = English
+	case Portuguese, "portuguese":
+		name = Portuguese
+	}
+
+	if m, ok := Builtin(name); ok {
+		return m, nil
+	}
+
+	if !strings.ContainsAny(name, "./\\") {
+		return nil, fmt.Errorf("unknown language model %q, expected %s, %s or a model file", name, English, Portuguese)
+	}
+
+	return LoadFile(name)
+}
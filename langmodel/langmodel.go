@@ -0,0 +1,298 @@
+package langmodel
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Orders of the n-grams counted by a model
+const (
+	Unigram  = 1
+	Bigram   = 2
+	Quadgram = 4
+)
+
+// Orders lists the n-gram orders of every model, lowest first
+var Orders = []int{Unigram, Bigram, Quadgram}
+
+// ErrEmptyCorpus is returned when training on a corpus without any letter
+var ErrEmptyCorpus = errors.New("corpus has no letters")
+
+// folds maps the accented letters of Latin languages to the letter they are written over
+var folds = map[rune]rune{
+	'á': 'a', 'à': 'a', 'â': 'a', 'ã': 'a', 'ä': 'a',
+	'é': 'e', 'è': 'e', 'ê': 'e', 'ë': 'e',
+	'í': 'i', 'ì': 'i', 'î': 'i', 'ï': 'i',
+	'ó': 'o', 'ò': 'o', 'ô': 'o', 'õ': 'o', 'ö': 'o',
+	'ú': 'u', 'ù': 'u', 'û': 'u', 'ü': 'u',
+	'ç': 'c', 'ñ': 'n',
+}
+
+// fold returns the lowercase letter a-z of char, or false when it is not a letter of the alphabet
+func fold(char rune) (rune, bool) {
+	switch {
+	case char >= 'a' && char <= 'z':
+		return char, true
+	case char >= 'A' && char <= 'Z':
+		return char + 'a' - 'A', true
+	case char < unicode.MaxASCII:
+		return char, false
+	}
+
+	char = unicode.ToLower(char)
+	if folded, ok := folds[char]; ok {
+		char = folded
+	}
+
+	return char, char >= 'a' && char <= 'z'
+}
+
+// Letters returns the letters of text folded to lowercase a-z, dropping everything else
+func Letters(text string) string {
+	var b strings.Builder
+	for _, char := range text {
+		if folded, ok := fold(char); ok {
+			b.WriteRune(folded)
+		}
+	}
+
+	return b.String()
+}
+
+// Model holds the n-gram log-probabilities of a language
+type Model struct {
+	Name string
+
+	counts map[int]map[string]int
+	// logp holds the log-probability of every n-gram by order, indexed by its letters in base 26.
+	// N-grams never seen while training get a floor below the rarest one seen.
+	logp map[int][]float64
+}
+
+// Train counts the unigrams, bigrams and quadgrams of the letters read from r
+func Train(name string, r io.Reader) (*Model, error) {
+	counts := make(map[int]map[string]int, len(Orders))
+	for _, n := range Orders {
+		counts[n] = make(map[string]int)
+	}
+
+	window := make([]rune, 0, Quadgram)
+	br := bufio.NewReader(r)
+	for {
+		char, _, err := br.ReadRune()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		folded, ok := fold(char)
+		if !ok {
+			continue
+		}
+
+		if len(window) == Quadgram {
+			window = window[1:]
+		}
+		window = append(window, folded)
+
+		for _, n := range Orders {
+			if len(window) >= n {
+				counts[n][string(window[len(window)-n:])]++
+			}
+		}
+	}
+
+	return newModel(name, counts)
+}
+
+// TrainFile trains a model on the corpus held by the named file
+func TrainFile(name, file string) (*Model, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Train(name, f)
+}
+
+// newModel computes the log-probabilities of the n-gram counts
+func newModel(name string, counts map[int]map[string]int) (*Model, error) {
+	m := &Model{Name: name, counts: counts, logp: make(map[int][]float64, len(Orders))}
+
+	for _, n := range Orders {
+		total := 0
+		for _, count := range counts[n] {
+			total += count
+		}
+
+		if total == 0 {
+			return nil, ErrEmptyCorpus
+		}
+
+		size := 1
+		for i := 0; i < n; i++ {
+			size *= 26
+		}
+
+		floor := math.Log(0.01 / float64(total))
+		m.logp[n] = make([]float64, size)
+		for i := range m.logp[n] {
+			m.logp[n][i] = floor
+		}
+
+		for gram, count := range counts[n] {
+			m.logp[n][index(gram)] = math.Log(float64(count) / float64(total))
+		}
+	}
+
+	return m, nil
+}
+
+// index returns the position of the n-gram of letters a-z in the table of its order
+func index(gram string) int {
+	i := 0
+	for _, char := range []byte(gram) {
+		i = i*26 + int(char-'a')
+	}
+
+	return i
+}
+
+// LogProb returns the sum of the log-probabilities of every n-gram of order n in the letters of text
+func (m *Model) LogProb(text string, n int) float64 {
+	logp, ok := m.logp[n]
+	if !ok {
+		panic(fmt.Sprintf("langmodel: no %d-grams in model %s", n, m.Name))
+	}
+
+	// i rolls the index of the last n letters, dropping the oldest one as each letter is read
+	score, i, seen := 0.0, 0, 0
+	for _, char := range text {
+		folded, ok := fold(char)
+		if !ok {
+			continue
+		}
+
+		i = (i*26 + int(folded-'a')) % len(logp)
+		if seen++; seen >= n {
+			score += logp[i]
+		}
+	}
+
+	return score
+}
+
+// Frequency returns the probability of the letter a-z in the language
+func (m *Model) Frequency(letter rune) float64 {
+	if letter < 'a' || letter > 'z' || m.counts[Unigram][string(letter)] == 0 {
+		return 0
+	}
+
+	return math.Exp(m.logp[Unigram][letter-'a'])
+}
+
+// Unigram returns the log-probability of the letters of text
+func (m *Model) Unigram(text string) float64 {
+	return m.LogProb(text, Unigram)
+}
+
+// Bigram returns the log-probability of the letter pairs of text
+func (m *Model) Bigram(text string) float64 {
+	return m.LogProb(text, Bigram)
+}
+
+// Quadgram returns the log-probability of the groups of four letters of text
+func (m *Model) Quadgram(text string) float64 {
+	return m.LogProb(text, Quadgram)
+}
+
+// Score returns the log-probability of text under the highest order that fits its letters,
+// higher is better. It can be passed to crypto.CrackWith.
+func (m *Model) Score(text string) float64 {
+	letters := len(Letters(text))
+	for i := len(Orders) - 1; i > 0; i-- {
+		if letters >= Orders[i] {
+			return m.LogProb(text, Orders[i])
+		}
+	}
+
+	return m.LogProb(text, Orders[0])
+}
+
+// saved is the JSON form of a model, n-gram counts keyed by their order
+type saved struct {
+	Name   string                    `json:"name"`
+	Counts map[string]map[string]int `json:"counts"`
+}
+
+// Save writes the n-gram counts of the model as JSON
+func (m *Model) Save(w io.Writer) error {
+	s := saved{Name: m.Name, Counts: make(map[string]map[string]int, len(Orders))}
+	for _, n := range Orders {
+		s.Counts[strconv.Itoa(n)] = m.counts[n]
+	}
+
+	return json.NewEncoder(w).Encode(s)
+}
+
+// SaveFile writes the model to the named file
+func (m *Model) SaveFile(file string) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+
+	if err := m.Save(f); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// Load reads a model written by Save
+func Load(r io.Reader) (*Model, error) {
+	var s saved
+	if err := json.NewDecoder(r).Decode(&s); err != nil {
+		return nil, fmt.Errorf("langmodel: %w", err)
+	}
+
+	counts := make(map[int]map[string]int, len(Orders))
+	for _, n := range Orders {
+		grams, ok := s.Counts[strconv.Itoa(n)]
+		if !ok {
+			return nil, fmt.Errorf("langmodel: model %s has no %d-grams", s.Name, n)
+		}
+
+		for gram, count := range grams {
+			if len(gram) != n || Letters(gram) != gram || count < 0 {
+				return nil, fmt.Errorf("langmodel: invalid %d-gram %q in model %s", n, gram, s.Name)
+			}
+		}
+		counts[n] = grams
+	}
+
+	return newModel(s.Name, counts)
+}
+
+// LoadFile reads the model saved in the named file
+func LoadFile(file string) (*Model, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Load(f)
+}
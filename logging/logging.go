@@ -0,0 +1,170 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"github.com/wesleyholiveira/caesar-challenge/config"
+)
+
+// RunIDKey is the attribute holding the run ID on every line logged during a run
+const RunIDKey = "run_id"
+
+// Mask replaces redacted secrets
+const Mask = "********"
+
+// Output formats accepted by New
+const (
+	Text = "text"
+	JSON = "json"
+)
+
+var tokenParam = regexp.MustCompile(`(?i)(token=)[^&\s"']+`)
+
+// New returns a logger writing lines at level or above to w, as key=value text or as JSON.
+// Every line goes through Redact, see ReplaceAttr.
+func New(w io.Writer, level slog.Level, output string) (*slog.Logger, error) {
+	opts := &slog.HandlerOptions{Level: level, ReplaceAttr: ReplaceAttr}
+
+	switch strings.ToLower(output) {
+	case Text, "":
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	case JSON:
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	}
+
+	return nil, fmt.Errorf("unknown log format %q, expected %s or %s", output, Text, JSON)
+}
+
+// ParseLevel reads debug, info, warn or error, an empty level is info
+func ParseLevel(s string) (slog.Level, error) {
+	var level slog.Level
+	if s == "" {
+		return slog.LevelInfo, nil
+	}
+
+	if err := level.UnmarshalText([]byte(s)); err != nil {
+		return 0, fmt.Errorf("unknown log level %q, expected debug, info, warn or error", s)
+	}
+
+	return level, nil
+}
+
+// Or returns l, or a logger discarding every line when l is nil
+func Or(l *slog.Logger) *slog.Logger {
+	if l == nil {
+		return slog.New(slog.DiscardHandler)
+	}
+
+	return l
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying logger, see FromContext
+func NewContext(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the logger carried by ctx, or Default when there is none
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(contextKey{}).(*slog.Logger); ok && logger != nil {
+		return logger
+	}
+
+	return Default()
+}
+
+// Default returns slog.Default with every line redacted, for callers given no logger
+func Default() *slog.Logger {
+	return slog.New(Redacting(slog.Default().Handler()))
+}
+
+// Redacting returns a handler passing every record to h once its message and attributes
+// went through ReplaceAttr, for handlers not built by New
+func Redacting(h slog.Handler) slog.Handler {
+	return &redactingHandler{handler: h}
+}
+
+type redactingHandler struct {
+	handler slog.Handler
+	groups  []string
+}
+
+func (h *redactingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *redactingHandler) Handle(ctx context.Context, r slog.Record) error {
+	redacted := slog.NewRecord(r.Time, r.Level, Redact(r.Message), r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		redacted.AddAttrs(redactAttr(h.groups, a))
+		return true
+	})
+
+	return h.handler.Handle(ctx, redacted)
+}
+
+func (h *redactingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		redacted[i] = redactAttr(h.groups, a)
+	}
+
+	return &redactingHandler{handler: h.handler.WithAttrs(redacted), groups: h.groups}
+}
+
+func (h *redactingHandler) WithGroup(name string) slog.Handler {
+	groups := append(append([]string{}, h.groups...), name)
+	return &redactingHandler{handler: h.handler.WithGroup(name), groups: groups}
+}
+
+// redactAttr applies ReplaceAttr to a, and to every attribute of a group
+func redactAttr(groups []string, a slog.Attr) slog.Attr {
+	a.Value = a.Value.Resolve()
+	if a.Value.Kind() != slog.KindGroup {
+		return ReplaceAttr(groups, a)
+	}
+
+	members := a.Value.Group()
+	redacted := make([]interface{}, len(members))
+	for i, m := range members {
+		redacted[i] = redactAttr(append(groups[:len(groups):len(groups)], a.Key), m)
+	}
+
+	return slog.Group(a.Key, redacted...)
+}
+
+// Redact masks config.TokenCodeNation and the value of every token query parameter in s
+func Redact(s string) string {
+	if config.TokenCodeNation != "" {
+		s = strings.ReplaceAll(s, config.TokenCodeNation, Mask)
+	}
+
+	return tokenParam.ReplaceAllString(s, "${1}"+Mask)
+}
+
+// ReplaceAttr redacts the message and every attribute, attributes named token are masked
+// whatever their value. Values that are not strings, such as errors, are formatted first
+// when they hold a secret.
+func ReplaceAttr(groups []string, a slog.Attr) slog.Attr {
+	if strings.EqualFold(a.Key, "token") {
+		return slog.String(a.Key, Mask)
+	}
+
+	switch a.Value.Kind() {
+	case slog.KindString:
+		a.Value = slog.StringValue(Redact(a.Value.String()))
+	case slog.KindAny:
+		s := fmt.Sprint(a.Value.Any())
+		if redacted := Redact(s); redacted != s {
+			a.Value = slog.StringValue(redacted)
+		}
+	}
+
+	return a
+}
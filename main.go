@@ -1,25 +1,11 @@
 package main
 
 import (
-	"log"
+	"os"
 
-	"github.com/wesleyholiveira/caesar-challenge/crypto"
-	"github.com/wesleyholiveira/caesar-challenge/request"
+	"github.com/wesleyholiveira/caesar-challenge/cli"
 )
 
 func main() {
-	w, err := request.GetCryptedText("./answer.json")
-
-	if err != nil {
-		log.Panicln(err)
-	}
-
-	crypto.Decrypt(w)
-
-	respBody, err := request.PostSubmitData("./answer.json")
-	if err != nil {
-		log.Panicln(err)
-	}
-
-	log.Println(string(respBody))
+	os.Exit(cli.New().Run(os.Args[1:]))
 }
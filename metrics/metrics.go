@@ -0,0 +1,119 @@
+package metrics
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Registry holds every metric of the application, it is kept apart from the
+// prometheus default registry so dumps only contain what is defined here
+var Registry = prometheus.NewRegistry()
+
+var factory = promauto.With(Registry)
+
+var (
+	// RequestDuration observes the latency of the challenge API calls by endpoint and method
+	RequestDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "caesar_request_duration_seconds",
+		Help:    "Latency of the challenge API requests.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"endpoint", "method"})
+
+	// Requests counts the challenge API calls by endpoint and status code, "error" when no response came back
+	Requests = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "caesar_requests_total",
+		Help: "Challenge API requests by status code.",
+	}, []string{"endpoint", "status"})
+
+	// Retries counts the challenge API calls sent again after a failure
+	Retries = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "caesar_request_retries_total",
+		Help: "Challenge API requests retried after a failure.",
+	}, []string{"endpoint"})
+
+	// DecryptedBytes counts the bytes of ciphertext decrypted
+	DecryptedBytes = factory.NewCounter(prometheus.CounterOpts{
+		Name: "caesar_decrypted_bytes_total",
+		Help: "Bytes of ciphertext decrypted.",
+	})
+
+	// CrackAttempts counts the keys tried while cracking ciphertexts
+	CrackAttempts = factory.NewCounter(prometheus.CounterOpts{
+		Name: "caesar_crack_attempts_total",
+		Help: "Keys tried while cracking ciphertexts.",
+	})
+)
+
+// Endpoint returns the last element of the path of rawURL, such as generate-data
+func Endpoint(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Path == "" {
+		return "unknown"
+	}
+
+	return path.Base(u.Path)
+}
+
+// ObserveRequest records a completed API call, status is zero when it failed without a response
+func ObserveRequest(rawURL, method string, status int, d time.Duration) {
+	endpoint := Endpoint(rawURL)
+	RequestDuration.WithLabelValues(endpoint, method).Observe(d.Seconds())
+
+	code := "error"
+	if status != 0 {
+		code = strconv.Itoa(status)
+	}
+
+	Requests.WithLabelValues(endpoint, code).Inc()
+}
+
+// ObserveDecrypt counts the bytes of ciphertext decrypted, the cipher packages
+// are not instrumented so it is called by the commands and servers using them
+func ObserveDecrypt(text string) {
+	DecryptedBytes.Add(float64(len(text)))
+}
+
+// ObserveCrack counts the keys tried while cracking a ciphertext
+func ObserveCrack(attempts int) {
+	CrackAttempts.Add(float64(attempts))
+}
+
+// Handler serves Registry in the prometheus text format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// WriteFile atomically writes Registry to path in the prometheus text format,
+// for one-shot runs read by the node exporter textfile collector or by hand
+func WriteFile(path string) error {
+	return prometheus.WriteToTextfile(path, Registry)
+}
+
+// Serve exposes Handler under /metrics on l until ctx is done
+func Serve(ctx context.Context, l net.Listener) error {
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", Handler())
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}()
+
+	if err := srv.Serve(l); err != http.ErrServerClosed {
+		return err
+	}
+
+	return nil
+}
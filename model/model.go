@@ -0,0 +1,12 @@
+package model
+
+// ChallengeResponse struct deals with the http response
+type ChallengeResponse struct {
+	Places        int    `json:"numero_casas" yaml:"numero_casas" toml:"numero_casas"`
+	Token         string `json:"token" yaml:"token" toml:"token"`
+	CryptedText   string `json:"cifrado" yaml:"cifrado" toml:"cifrado"`
+	DecryptedText string `json:"decifrado" yaml:"decifrado" toml:"decifrado"`
+	SummaryCrypto string `json:"resumo_criptografico" yaml:"resumo_criptografico" toml:"resumo_criptografico"`
+	// Pipeline is the spec of the pipeline the answer was decrypted with, empty for a Caesar shift by Places
+	Pipeline string `json:"pipeline,omitempty" yaml:"pipeline,omitempty" toml:"pipeline,omitempty"`
+}
@@ -1,7 +1,16 @@
 package reader
 
 import (
+	"context"
+	"fmt"
 	"os"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/wesleyholiveira/caesar-challenge/config"
+	"github.com/wesleyholiveira/caesar-challenge/format"
+	"github.com/wesleyholiveira/caesar-challenge/model"
+	"github.com/wesleyholiveira/caesar-challenge/tracing"
 )
 
 type ReaderAnswer struct {
@@ -9,20 +18,53 @@ type ReaderAnswer struct {
 	Data []byte
 }
 
+// ReadAnswer reads the whole answer file, see ReadAnswerLimit
 func ReadAnswer(f string) (*ReaderAnswer, error) {
+	return ReadAnswerContext(context.Background(), f)
+}
+
+// ReadAnswerContext is ReadAnswer traced as a span of ctx
+func ReadAnswerContext(ctx context.Context, f string) (r *ReaderAnswer, err error) {
+	_, span := tracing.Start(ctx, "reader.ReadAnswer", attribute.String("file", f))
+	defer func() { tracing.End(span, err) }()
+
+	if r, err = ReadAnswerLimit(f, config.MaxInputSize); err == nil {
+		span.SetAttributes(attribute.Int("bytes", len(r.Data)))
+	}
+
+	return r, err
+}
+
+// ReadAnswerLimit reads the answer file, or the standard input for Stdin, failing
+// with ErrTooLarge when it holds more than limit bytes. Gzip compressed files are
+// decompressed and the limit applies to the decompressed data.
+func ReadAnswerLimit(f string, limit int64) (*ReaderAnswer, error) {
 	reader := &ReaderAnswer{}
 
-	file, err := os.Open(f)
+	file, err := Open(f)
+	if err != nil {
+		return nil, err
+	}
+
 	defer file.Close()
 
+	var stat os.FileInfo
+	if f == Stdin {
+		stat, err = os.Stdin.Stat()
+	} else {
+		stat, err = os.Stat(f)
+	}
+
 	if err != nil {
 		return nil, err
 	}
 
-	stat, _ := file.Stat()
-	data := make([]byte, stat.Size())
+	if stat.IsDir() {
+		return nil, fmt.Errorf("%s is a directory", f)
+	}
 
-	if _, err := file.Read(data); err != nil {
+	data, err := ReadLimit(file, limit)
+	if err != nil {
 		return nil, err
 	}
 
@@ -30,3 +72,31 @@ func ReadAnswer(f string) (*ReaderAnswer, error) {
 	reader.Info = stat
 	return reader, nil
 }
+
+// ReadChallenge reads the answer file and decodes it with dec, which defaults to
+// config.AnswerFormat or the format matching the file extension
+func ReadChallenge(f string, dec format.Decoder) (*model.ChallengeResponse, error) {
+	return ReadChallengeContext(context.Background(), f, dec)
+}
+
+// ReadChallengeContext is ReadChallenge reading the answer in a span of ctx
+func ReadChallengeContext(ctx context.Context, f string, dec format.Decoder) (*model.ChallengeResponse, error) {
+	if dec == nil {
+		var err error
+		if dec, err = format.Resolve(config.AnswerFormat, f); err != nil {
+			return nil, err
+		}
+	}
+
+	r, err := ReadAnswerContext(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &model.ChallengeResponse{}
+	if err := dec.Decode(r.Data, response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
@@ -0,0 +1,110 @@
+package reader
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Stdin is the file name that reads from the standard input
+const Stdin = "-"
+
+// ErrTooLarge is returned when an input is bigger than the requested limit
+var ErrTooLarge = errors.New("input exceeds size limit")
+
+var gzipMagic = []byte{0x1f, 0x8b}
+
+type readCloser struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (r *readCloser) Close() error {
+	var err error
+	for _, c := range r.closers {
+		if cerr := c.Close(); err == nil {
+			err = cerr
+		}
+	}
+
+	return err
+}
+
+// Decompress returns a reader for r that transparently inflates gzip data,
+// detected by its magic number so it works for pipes and standard input as well
+func Decompress(r io.Reader) (io.ReadCloser, error) {
+	buf := bufio.NewReader(r)
+	magic, err := buf.Peek(len(gzipMagic))
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	if !bytes.Equal(magic, gzipMagic) {
+		return io.NopCloser(buf), nil
+	}
+
+	return gzip.NewReader(buf)
+}
+
+// Open opens the named file, or the standard input for Stdin, decompressing gzip data
+func Open(name string) (io.ReadCloser, error) {
+	var file *os.File
+	if name == Stdin {
+		file = os.Stdin
+	} else {
+		var err error
+		if file, err = os.Open(name); err != nil {
+			return nil, err
+		}
+	}
+
+	r, err := Decompress(file)
+	if err != nil {
+		if name != Stdin {
+			file.Close()
+		}
+		return nil, err
+	}
+
+	closers := []io.Closer{r}
+	if name != Stdin {
+		closers = append(closers, file)
+	}
+
+	return &readCloser{Reader: r, closers: closers}, nil
+}
+
+// ReadLimit reads r until EOF, failing with ErrTooLarge after limit bytes.
+// A limit lower or equal to zero reads without limit.
+func ReadLimit(r io.Reader, limit int64) ([]byte, error) {
+	if limit <= 0 {
+		return io.ReadAll(r)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("%w of %d bytes", ErrTooLarge, limit)
+	}
+
+	return data, nil
+}
+
+// ReadFile reads the whole named file, or the standard input for Stdin, see Open and ReadLimit
+func ReadFile(name string, limit int64) ([]byte, error) {
+	r, err := Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.Close()
+
+	return ReadLimit(r, limit)
+}
@@ -0,0 +1,73 @@
+package reader
+
+import (
+	"fmt"
+
+	"github.com/wesleyholiveira/caesar-challenge/crypto"
+	"github.com/wesleyholiveira/caesar-challenge/format"
+	"github.com/wesleyholiveira/caesar-challenge/model"
+)
+
+// Problem describes why a field of an answer is invalid
+type Problem struct {
+	Field   string
+	Message string
+}
+
+func (p Problem) Error() string {
+	return fmt.Sprintf("%s: %s", p.Field, p.Message)
+}
+
+// Validate checks that every field of the answer is present and consistent:
+// numero_casas is within the accepted shifts, unless the answer records a pipeline,
+// decifrado is the decryption of cifrado and resumo_criptografico is the SHA-1 of decifrado
+func Validate(r *model.ChallengeResponse) []Problem {
+	problems := []Problem{}
+
+	required := []struct {
+		field string
+		value string
+	}{
+		{"token", r.Token},
+		{"cifrado", r.CryptedText},
+		{"decifrado", r.DecryptedText},
+		{"resumo_criptografico", r.SummaryCrypto},
+	}
+
+	for _, req := range required {
+		if req.value == "" {
+			problems = append(problems, Problem{req.field, "is required"})
+		}
+	}
+
+	if r.Pipeline == "" && (r.Places < crypto.MinPlaces || r.Places > crypto.MaxPlaces) {
+		problems = append(problems, Problem{"numero_casas", fmt.Sprintf("%d is out of range [%d, %d]", r.Places, crypto.MinPlaces, crypto.MaxPlaces)})
+	} else if r.CryptedText != "" && r.DecryptedText != "" {
+		expected, err := crypto.DecryptAnswer(r)
+		switch {
+		case err != nil:
+			problems = append(problems, Problem{"pipeline", err.Error()})
+		case r.DecryptedText != expected:
+			problems = append(problems, Problem{"decifrado", fmt.Sprintf("is not the decryption of cifrado, expected %q", expected)})
+		}
+	}
+
+	if r.DecryptedText != "" && r.SummaryCrypto != "" {
+		if expected := crypto.Summary(r.DecryptedText); r.SummaryCrypto != expected {
+			problems = append(problems, Problem{"resumo_criptografico", fmt.Sprintf("is not the SHA-1 of decifrado, expected %s", expected)})
+		}
+	}
+
+	return problems
+}
+
+// ReadValidChallenge decodes the answer file like ReadChallenge and validates it.
+// The error is only set when the file cannot be read or decoded.
+func ReadValidChallenge(f string, dec format.Decoder) (*model.ChallengeResponse, []Problem, error) {
+	response, err := ReadChallenge(f, dec)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, Validate(response), nil
+}
@@ -2,42 +2,134 @@ package request
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
-	"log"
+	"log/slog"
 	"mime/multipart"
 	"net/http"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/wesleyholiveira/caesar-challenge/config"
+	"github.com/wesleyholiveira/caesar-challenge/format"
+	"github.com/wesleyholiveira/caesar-challenge/logging"
+	"github.com/wesleyholiveira/caesar-challenge/metrics"
+	"github.com/wesleyholiveira/caesar-challenge/model"
 	"github.com/wesleyholiveira/caesar-challenge/reader"
+	"github.com/wesleyholiveira/caesar-challenge/tracing"
 	"github.com/wesleyholiveira/caesar-challenge/writer"
 )
 
 // ChallengeResponse struct deals with the http response
-type ChallengeResponse struct {
-	Places        int    `json:"numero_casas"`
-	Token         string `json:"token"`
-	CryptedText   string `json:"cifrado"`
-	DecryptedText string `json:"decifrado"`
-	SummaryCrypto string `json:"resumo_criptografico"`
-}
+type ChallengeResponse = model.ChallengeResponse
+
+// Defaults used when GetCryptedText or PostSubmitData receive nil functions,
+// the parameters shadow the package level functions
+var (
+	defaultGetRequest    = getRequest
+	defaultParseResponse = parseResponse
+	defaultPostRequest   = postRequest
+)
 
+// getRequest is the default http GET, logging to logging.Default
 func getRequest(url string) ([]byte, error) {
-	log.Printf("Making request to %s", url)
+	return NewGetRequest(context.Background(), logging.Default())(url)
+}
+
+// postRequest is the default http POST, logging to logging.Default
+func postRequest(url string, body *bytes.Buffer) ([]byte, error) {
+	return NewPostRequest(context.Background(), logging.Default())(url, body)
+}
+
+// NewGetRequest returns the http GET used by GetCryptedText, logging every call to logger
+// with the token redacted. A nil logger logs nothing. The calls are bound to ctx and traced
+// as its children. Failed calls and 5xx responses are retried up to config.MaxRetries
+// times, waiting config.RetryDelay longer after each attempt unless ctx is done.
+func NewGetRequest(ctx context.Context, logger *slog.Logger) func(string) ([]byte, error) {
+	logger = logging.Or(logger)
+	return func(url string) ([]byte, error) {
+		for attempt := 1; ; attempt++ {
+			body, status, err := do(logger, http.MethodGet, url, func() (*http.Response, error) {
+				req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+				if err != nil {
+					return nil, err
+				}
+
+				return tracing.Client.Do(req)
+			})
+
+			if attempt > config.MaxRetries || (err == nil && status < http.StatusInternalServerError) {
+				return body, err
+			}
+
+			metrics.Retries.WithLabelValues(metrics.Endpoint(url)).Inc()
+			logger.Warn("retrying request", "method", http.MethodGet, "url", url, "attempt", attempt, "status", status, "error", err)
+			if err := wait(ctx, config.RetryDelay*time.Duration(attempt)); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// wait returns after d, or with the error of ctx when it is done first
+func wait(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// NewPostRequest returns the http POST used by PostSubmitData, logging every call to logger
+// with the token redacted. A nil logger logs nothing. The calls are bound to ctx and traced
+// as its children. Submissions are never retried since the first one may have been received.
+func NewPostRequest(ctx context.Context, logger *slog.Logger) func(string, *bytes.Buffer) ([]byte, error) {
+	logger = logging.Or(logger)
+	return func(url string, body *bytes.Buffer) ([]byte, error) {
+		respBody, _, err := do(logger, http.MethodPost, url, func() (*http.Response, error) {
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, body)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("Content-Type", "multipart/form-data")
+			return tracing.Client.Do(req)
+		})
+
+		return respBody, err
+	}
+}
+
+// do sends the request and reads the response body, logging and measuring its status and duration
+func do(logger *slog.Logger, method, url string, send func() (*http.Response, error)) ([]byte, int, error) {
+	start := time.Now()
+	logger.Debug("sending request", "method", method, "url", url)
 
-	resp, err := http.Get(url)
+	resp, err := send()
 	if err != nil {
-		return nil, err
+		metrics.ObserveRequest(url, method, 0, time.Since(start))
+		logger.Error("request failed", "method", method, "url", url, "duration", time.Since(start), "error", err)
+		return nil, 0, err
 	}
 
 	defer resp.Body.Close()
 	body, err := ioutil.ReadAll(resp.Body)
+	metrics.ObserveRequest(url, method, resp.StatusCode, time.Since(start))
 	if err != nil {
-		return nil, err
+		logger.Error("reading response failed", "method", method, "url", url, "status", resp.StatusCode, "error", err)
+		return nil, resp.StatusCode, err
 	}
 
-	return body, nil
+	logger.Info("request done", "method", method, "url", url, "status", resp.StatusCode, "bytes", len(body), "duration", time.Since(start))
+	return body, resp.StatusCode, nil
 }
 
 func parseResponse(body []byte) (*ChallengeResponse, error) {
@@ -50,8 +142,31 @@ func parseResponse(body []byte) (*ChallengeResponse, error) {
 	return response, nil
 }
 
-// GetCryptedText sends request to codenation and return a struct with the json parsed
+// GetCryptedText sends request to codenation and return a struct with the json parsed.
+// Nil functions fall back to the default http request and json parsing.
 func GetCryptedText(file string, getRequest func(string) ([]byte, error), parseResponse func([]byte) (*ChallengeResponse, error)) (*writer.WriterAnswer, error) {
+	if getRequest == nil {
+		getRequest = defaultGetRequest
+	}
+
+	return GetCryptedTextContext(context.Background(), file, nil, getRequest, parseResponse)
+}
+
+// GetCryptedTextContext is GetCryptedText traced as a span of ctx writing the answer in
+// the format f, see writer.WriterAnswer. A nil getRequest sends the request in a child
+// span and logs to the logger of ctx, see logging.FromContext
+func GetCryptedTextContext(ctx context.Context, file string, f format.Format, getRequest func(string) ([]byte, error), parseResponse func([]byte) (*ChallengeResponse, error)) (_ *writer.WriterAnswer, err error) {
+	ctx, span := tracing.Start(ctx, "request.GetCryptedText", attribute.String("file", file))
+	defer func() { tracing.End(span, err) }()
+
+	if getRequest == nil {
+		getRequest = NewGetRequest(ctx, logging.FromContext(ctx))
+	}
+
+	if parseResponse == nil {
+		parseResponse = defaultParseResponse
+	}
+
 	w := writer.New()
 	url := fmt.Sprintf("%s?token=%s", config.GenerateUrl, config.TokenCodeNation)
 
@@ -66,58 +181,165 @@ func GetCryptedText(file string, getRequest func(string) ([]byte, error), parseR
 	}
 
 	w.File = file
+	w.Format = f
 	w.Response = response
 	w.Data = body
-	writer.WriteAnswer(w)
+	if err := writer.WriteAnswerContext(ctx, w); err != nil {
+		return nil, err
+	}
 
 	return w, nil
 }
 
-func postRequest(url string, body *bytes.Buffer) ([]byte, error) {
-	log.Printf("Making request to %s", url)
+// SubmitPayload is the multipart request PostSubmitData sends
+type SubmitPayload struct {
+	URL  string
+	Body *bytes.Buffer
+	// Token is the answer token, masked by Masked along with config.TokenCodeNation
+	Token string
+}
 
-	resp, err := http.Post(url, "multipart/form-data", body)
+// NewSubmitPayload builds the multipart request submitting the answer file stored in the
+// format f, nil defaults to config.AnswerFormat or the one matching the file extension.
+// Answers stored in a format other than JSON are converted.
+func NewSubmitPayload(file string, f format.Format) (*SubmitPayload, error) {
+	return newSubmitPayload(context.Background(), file, f)
+}
+
+func newSubmitPayload(ctx context.Context, file string, f format.Format) (*SubmitPayload, error) {
+	name, data, err := readSubmission(ctx, file, f)
 	if err != nil {
 		return nil, err
 	}
 
-	defer resp.Body.Close()
-	respBody, err := ioutil.ReadAll(resp.Body)
+	return submitPayload(name, data)
+}
+
+// submitPayload builds the multipart request submitting data, the JSON answer, as the named file
+func submitPayload(name string, data []byte) (*SubmitPayload, error) {
+	url := fmt.Sprintf("%s?token=%s", config.SubmitUrl, config.TokenCodeNation)
+
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("answer", name)
 	if err != nil {
 		return nil, err
 	}
 
-	return respBody, nil
+	if _, err := part.Write(data); err != nil {
+		return nil, err
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	response := &ChallengeResponse{}
+	json.Unmarshal(data, response)
+
+	return &SubmitPayload{URL: url, Body: body, Token: response.Token}, nil
+}
+
+// Masked returns the request line and body with every token replaced by a mask
+func (p *SubmitPayload) Masked() string {
+	return MaskToken(fmt.Sprintf("POST %s\n\n%s", p.URL, p.Body.String()), config.TokenCodeNation, p.Token)
+}
+
+// MaskToken replaces every occurrence of the tokens in s
+func MaskToken(s string, tokens ...string) string {
+	for _, token := range tokens {
+		if token != "" {
+			s = strings.ReplaceAll(s, token, logging.Mask)
+		}
+	}
+
+	return s
 }
 
-// PostSubmitData sends a POST request to submit the data
+// PostSubmitData sends a POST request to submit the data, see NewSubmitPayload.
+// A nil postRequest falls back to the default http request.
 func PostSubmitData(file string, postRequest func(string, *bytes.Buffer) ([]byte, error)) ([]byte, error) {
-	url := fmt.Sprintf("%s?token=%s", config.SubmitUrl, config.TokenCodeNation)
+	if postRequest == nil {
+		postRequest = defaultPostRequest
+	}
+
+	return PostSubmitDataContext(context.Background(), file, nil, postRequest)
+}
+
+// PostSubmitDataContext is PostSubmitData traced as a span of ctx reading the answer in
+// the format f, see NewSubmitPayload. A nil postRequest sends the request in a child
+// span and logs to the logger of ctx, see logging.FromContext
+func PostSubmitDataContext(ctx context.Context, file string, f format.Format, postRequest func(string, *bytes.Buffer) ([]byte, error)) (_ []byte, err error) {
+	ctx, span := tracing.Start(ctx, "request.PostSubmitData", attribute.String("file", file))
+	defer func() { tracing.End(span, err) }()
+
+	if postRequest == nil {
+		postRequest = NewPostRequest(ctx, logging.FromContext(ctx))
+	}
 
-	r, err := reader.ReadAnswer(file)
+	payload, err := newSubmitPayload(ctx, file, f)
 	if err != nil {
 		return nil, err
 	}
 
-	body := new(bytes.Buffer)
-	writer := multipart.NewWriter(body)
-	part, err := writer.CreateFormFile("answer", r.Info.Name())
+	respBody, err := postRequest(payload.URL, payload.Body)
 	if err != nil {
 		return nil, err
 	}
 
-	if _, err := part.Write(r.Data); err != nil {
-		return nil, err
+	return respBody, nil
+}
+
+// PostAnswerContext submits the answer as JSON without reading it from a file, such as an
+// answer recorded in the history, tracing it and defaulting postRequest as PostSubmitDataContext
+func PostAnswerContext(ctx context.Context, response *ChallengeResponse, postRequest func(string, *bytes.Buffer) ([]byte, error)) (_ []byte, err error) {
+	ctx, span := tracing.Start(ctx, "request.PostSubmitData")
+	defer func() { tracing.End(span, err) }()
+
+	if postRequest == nil {
+		postRequest = NewPostRequest(ctx, logging.FromContext(ctx))
 	}
 
-	if err := writer.Close(); err != nil {
+	data, err := format.JSON.Encode(response)
+	if err != nil {
 		return nil, err
 	}
 
-	respBody, err := postRequest(url, body)
+	payload, err := submitPayload("answer.json", data)
 	if err != nil {
 		return nil, err
 	}
 
-	return respBody, nil
+	return postRequest(payload.URL, payload.Body)
+}
+
+// readSubmission returns the file name and JSON content to submit for the answer file
+func readSubmission(ctx context.Context, file string, f format.Format) (string, []byte, error) {
+	if f == nil {
+		var err error
+		if f, err = format.Resolve(config.AnswerFormat, file); err != nil {
+			return "", nil, err
+		}
+	}
+
+	if f == format.JSON {
+		r, err := reader.ReadAnswerContext(ctx, file)
+		if err != nil {
+			return "", nil, err
+		}
+
+		return strings.TrimSuffix(r.Info.Name(), ".gz"), r.Data, nil
+	}
+
+	response, err := reader.ReadChallengeContext(ctx, file, f)
+	if err != nil {
+		return "", nil, err
+	}
+
+	data, err := format.JSON.Encode(response)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return "answer.json", data, nil
 }
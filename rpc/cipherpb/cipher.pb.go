@@ -0,0 +1,647 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.28.3
+// source: cipher.proto
+
+package cipherpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Step int32
+
+const (
+	Step_STEP_UNSPECIFIED Step = 0
+	Step_STEP_FETCH       Step = 1
+	Step_STEP_DECRYPT     Step = 2
+	Step_STEP_VERIFY      Step = 3
+	Step_STEP_SUBMIT      Step = 4
+	// The submission was built but not sent, replaces STEP_SUBMIT on dry runs
+	Step_STEP_DRY_RUN Step = 5
+)
+
+// Enum value maps for Step.
+var (
+	Step_name = map[int32]string{
+		0: "STEP_UNSPECIFIED",
+		1: "STEP_FETCH",
+		2: "STEP_DECRYPT",
+		3: "STEP_VERIFY",
+		4: "STEP_SUBMIT",
+		5: "STEP_DRY_RUN",
+	}
+	Step_value = map[string]int32{
+		"STEP_UNSPECIFIED": 0,
+		"STEP_FETCH":       1,
+		"STEP_DECRYPT":     2,
+		"STEP_VERIFY":      3,
+		"STEP_SUBMIT":      4,
+		"STEP_DRY_RUN":     5,
+	}
+)
+
+func (x Step) Enum() *Step {
+	p := new(Step)
+	*p = x
+	return p
+}
+
+func (x Step) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Step) Descriptor() protoreflect.EnumDescriptor {
+	return file_cipher_proto_enumTypes[0].Descriptor()
+}
+
+func (Step) Type() protoreflect.EnumType {
+	return &file_cipher_proto_enumTypes[0]
+}
+
+func (x Step) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Step.Descriptor instead.
+func (Step) EnumDescriptor() ([]byte, []int) {
+	return file_cipher_proto_rawDescGZIP(), []int{0}
+}
+
+type TextRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Text          string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	Places        int32                  `protobuf:"varint,2,opt,name=places,proto3" json:"places,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TextRequest) Reset() {
+	*x = TextRequest{}
+	mi := &file_cipher_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TextRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TextRequest) ProtoMessage() {}
+
+func (x *TextRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cipher_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TextRequest.ProtoReflect.Descriptor instead.
+func (*TextRequest) Descriptor() ([]byte, []int) {
+	return file_cipher_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *TextRequest) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *TextRequest) GetPlaces() int32 {
+	if x != nil {
+		return x.Places
+	}
+	return 0
+}
+
+type TextResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Text   string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	Places int32                  `protobuf:"varint,2,opt,name=places,proto3" json:"places,omitempty"`
+	// Hex encoded SHA-1 of the decrypted text
+	Summary       string `protobuf:"bytes,3,opt,name=summary,proto3" json:"summary,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TextResponse) Reset() {
+	*x = TextResponse{}
+	mi := &file_cipher_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TextResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TextResponse) ProtoMessage() {}
+
+func (x *TextResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cipher_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TextResponse.ProtoReflect.Descriptor instead.
+func (*TextResponse) Descriptor() ([]byte, []int) {
+	return file_cipher_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *TextResponse) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *TextResponse) GetPlaces() int32 {
+	if x != nil {
+		return x.Places
+	}
+	return 0
+}
+
+func (x *TextResponse) GetSummary() string {
+	if x != nil {
+		return x.Summary
+	}
+	return ""
+}
+
+type CrackRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Text  string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	// Number of candidates returned, zero returns all of them
+	Top           int32 `protobuf:"varint,2,opt,name=top,proto3" json:"top,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CrackRequest) Reset() {
+	*x = CrackRequest{}
+	mi := &file_cipher_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CrackRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CrackRequest) ProtoMessage() {}
+
+func (x *CrackRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cipher_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CrackRequest.ProtoReflect.Descriptor instead.
+func (*CrackRequest) Descriptor() ([]byte, []int) {
+	return file_cipher_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CrackRequest) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *CrackRequest) GetTop() int32 {
+	if x != nil {
+		return x.Top
+	}
+	return 0
+}
+
+type Candidate struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Places int32                  `protobuf:"varint,1,opt,name=places,proto3" json:"places,omitempty"`
+	Text   string                 `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
+	// Log-likelihood under English letter frequencies, higher is better
+	Score         float64 `protobuf:"fixed64,3,opt,name=score,proto3" json:"score,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Candidate) Reset() {
+	*x = Candidate{}
+	mi := &file_cipher_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Candidate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Candidate) ProtoMessage() {}
+
+func (x *Candidate) ProtoReflect() protoreflect.Message {
+	mi := &file_cipher_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Candidate.ProtoReflect.Descriptor instead.
+func (*Candidate) Descriptor() ([]byte, []int) {
+	return file_cipher_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Candidate) GetPlaces() int32 {
+	if x != nil {
+		return x.Places
+	}
+	return 0
+}
+
+func (x *Candidate) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *Candidate) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+type CrackResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Candidates    []*Candidate           `protobuf:"bytes,1,rep,name=candidates,proto3" json:"candidates,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CrackResponse) Reset() {
+	*x = CrackResponse{}
+	mi := &file_cipher_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CrackResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CrackResponse) ProtoMessage() {}
+
+func (x *CrackResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cipher_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CrackResponse.ProtoReflect.Descriptor instead.
+func (*CrackResponse) Descriptor() ([]byte, []int) {
+	return file_cipher_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CrackResponse) GetCandidates() []*Candidate {
+	if x != nil {
+		return x.Candidates
+	}
+	return nil
+}
+
+type RunChallengeRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Submit even when the answer does not verify
+	Force bool `protobuf:"varint,1,opt,name=force,proto3" json:"force,omitempty"`
+	// Stop before submitting and return the masked payload
+	DryRun bool `protobuf:"varint,2,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	// Use the challenge held by the answer file instead of fetching a new one
+	Load bool `protobuf:"varint,3,opt,name=load,proto3" json:"load,omitempty"`
+	// Submit again an answer that was submitted, or whose submit was interrupted
+	Resubmit bool `protobuf:"varint,4,opt,name=resubmit,proto3" json:"resubmit,omitempty"`
+	// Fetch a new challenge instead of resuming an interrupted run
+	Fresh         bool `protobuf:"varint,5,opt,name=fresh,proto3" json:"fresh,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RunChallengeRequest) Reset() {
+	*x = RunChallengeRequest{}
+	mi := &file_cipher_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RunChallengeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunChallengeRequest) ProtoMessage() {}
+
+func (x *RunChallengeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cipher_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunChallengeRequest.ProtoReflect.Descriptor instead.
+func (*RunChallengeRequest) Descriptor() ([]byte, []int) {
+	return file_cipher_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *RunChallengeRequest) GetForce() bool {
+	if x != nil {
+		return x.Force
+	}
+	return false
+}
+
+func (x *RunChallengeRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *RunChallengeRequest) GetLoad() bool {
+	if x != nil {
+		return x.Load
+	}
+	return false
+}
+
+func (x *RunChallengeRequest) GetResubmit() bool {
+	if x != nil {
+		return x.Resubmit
+	}
+	return false
+}
+
+func (x *RunChallengeRequest) GetFresh() bool {
+	if x != nil {
+		return x.Fresh
+	}
+	return false
+}
+
+type RunChallengeProgress struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Step that has just completed
+	Step          Step   `protobuf:"varint,1,opt,name=step,proto3,enum=caesar.v1.Step" json:"step,omitempty"`
+	CryptedText   string `protobuf:"bytes,2,opt,name=crypted_text,json=cryptedText,proto3" json:"crypted_text,omitempty"`
+	DecryptedText string `protobuf:"bytes,3,opt,name=decrypted_text,json=decryptedText,proto3" json:"decrypted_text,omitempty"`
+	Summary       string `protobuf:"bytes,4,opt,name=summary,proto3" json:"summary,omitempty"`
+	// Differences found by the verify step, empty when the answer verifies
+	Diffs []string `protobuf:"bytes,5,rep,name=diffs,proto3" json:"diffs,omitempty"`
+	// Body returned by submit-solution
+	Response []byte `protobuf:"bytes,6,opt,name=response,proto3" json:"response,omitempty"`
+	// Submission that would be sent, with the token masked, on dry runs
+	Payload       string `protobuf:"bytes,7,opt,name=payload,proto3" json:"payload,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RunChallengeProgress) Reset() {
+	*x = RunChallengeProgress{}
+	mi := &file_cipher_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RunChallengeProgress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunChallengeProgress) ProtoMessage() {}
+
+func (x *RunChallengeProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_cipher_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunChallengeProgress.ProtoReflect.Descriptor instead.
+func (*RunChallengeProgress) Descriptor() ([]byte, []int) {
+	return file_cipher_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *RunChallengeProgress) GetStep() Step {
+	if x != nil {
+		return x.Step
+	}
+	return Step_STEP_UNSPECIFIED
+}
+
+func (x *RunChallengeProgress) GetCryptedText() string {
+	if x != nil {
+		return x.CryptedText
+	}
+	return ""
+}
+
+func (x *RunChallengeProgress) GetDecryptedText() string {
+	if x != nil {
+		return x.DecryptedText
+	}
+	return ""
+}
+
+func (x *RunChallengeProgress) GetSummary() string {
+	if x != nil {
+		return x.Summary
+	}
+	return ""
+}
+
+func (x *RunChallengeProgress) GetDiffs() []string {
+	if x != nil {
+		return x.Diffs
+	}
+	return nil
+}
+
+func (x *RunChallengeProgress) GetResponse() []byte {
+	if x != nil {
+		return x.Response
+	}
+	return nil
+}
+
+func (x *RunChallengeProgress) GetPayload() string {
+	if x != nil {
+		return x.Payload
+	}
+	return ""
+}
+
+var File_cipher_proto protoreflect.FileDescriptor
+
+const file_cipher_proto_rawDesc = "" +
+	"\n" +
+	"\fcipher.proto\x12\tcaesar.v1\"9\n" +
+	"\vTextRequest\x12\x12\n" +
+	"\x04text\x18\x01 \x01(\tR\x04text\x12\x16\n" +
+	"\x06places\x18\x02 \x01(\x05R\x06places\"T\n" +
+	"\fTextResponse\x12\x12\n" +
+	"\x04text\x18\x01 \x01(\tR\x04text\x12\x16\n" +
+	"\x06places\x18\x02 \x01(\x05R\x06places\x12\x18\n" +
+	"\asummary\x18\x03 \x01(\tR\asummary\"4\n" +
+	"\fCrackRequest\x12\x12\n" +
+	"\x04text\x18\x01 \x01(\tR\x04text\x12\x10\n" +
+	"\x03top\x18\x02 \x01(\x05R\x03top\"M\n" +
+	"\tCandidate\x12\x16\n" +
+	"\x06places\x18\x01 \x01(\x05R\x06places\x12\x12\n" +
+	"\x04text\x18\x02 \x01(\tR\x04text\x12\x14\n" +
+	"\x05score\x18\x03 \x01(\x01R\x05score\"E\n" +
+	"\rCrackResponse\x124\n" +
+	"\n" +
+	"candidates\x18\x01 \x03(\v2\x14.caesar.v1.CandidateR\n" +
+	"candidates\"\x8a\x01\n" +
+	"\x13RunChallengeRequest\x12\x14\n" +
+	"\x05force\x18\x01 \x01(\bR\x05force\x12\x17\n" +
+	"\adry_run\x18\x02 \x01(\bR\x06dryRun\x12\x12\n" +
+	"\x04load\x18\x03 \x01(\bR\x04load\x12\x1a\n" +
+	"\bresubmit\x18\x04 \x01(\bR\bresubmit\x12\x14\n" +
+	"\x05fresh\x18\x05 \x01(\bR\x05fresh\"\xeb\x01\n" +
+	"\x14RunChallengeProgress\x12#\n" +
+	"\x04step\x18\x01 \x01(\x0e2\x0f.caesar.v1.StepR\x04step\x12!\n" +
+	"\fcrypted_text\x18\x02 \x01(\tR\vcryptedText\x12%\n" +
+	"\x0edecrypted_text\x18\x03 \x01(\tR\rdecryptedText\x12\x18\n" +
+	"\asummary\x18\x04 \x01(\tR\asummary\x12\x14\n" +
+	"\x05diffs\x18\x05 \x03(\tR\x05diffs\x12\x1a\n" +
+	"\bresponse\x18\x06 \x01(\fR\bresponse\x12\x18\n" +
+	"\apayload\x18\a \x01(\tR\apayload*r\n" +
+	"\x04Step\x12\x14\n" +
+	"\x10STEP_UNSPECIFIED\x10\x00\x12\x0e\n" +
+	"\n" +
+	"STEP_FETCH\x10\x01\x12\x10\n" +
+	"\fSTEP_DECRYPT\x10\x02\x12\x0f\n" +
+	"\vSTEP_VERIFY\x10\x03\x12\x0f\n" +
+	"\vSTEP_SUBMIT\x10\x04\x12\x10\n" +
+	"\fSTEP_DRY_RUN\x10\x052\x8f\x02\n" +
+	"\x06Cipher\x12:\n" +
+	"\aEncrypt\x12\x16.caesar.v1.TextRequest\x1a\x17.caesar.v1.TextResponse\x12:\n" +
+	"\aDecrypt\x12\x16.caesar.v1.TextRequest\x1a\x17.caesar.v1.TextResponse\x12:\n" +
+	"\x05Crack\x12\x17.caesar.v1.CrackRequest\x1a\x18.caesar.v1.CrackResponse\x12Q\n" +
+	"\fRunChallenge\x12\x1e.caesar.v1.RunChallengeRequest\x1a\x1f.caesar.v1.RunChallengeProgress0\x01B:Z8github.com/wesleyholiveira/caesar-challenge/rpc/cipherpbb\x06proto3"
+
+var (
+	file_cipher_proto_rawDescOnce sync.Once
+	file_cipher_proto_rawDescData []byte
+)
+
+func file_cipher_proto_rawDescGZIP() []byte {
+	file_cipher_proto_rawDescOnce.Do(func() {
+		file_cipher_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_cipher_proto_rawDesc), len(file_cipher_proto_rawDesc)))
+	})
+	return file_cipher_proto_rawDescData
+}
+
+var file_cipher_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_cipher_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_cipher_proto_goTypes = []any{
+	(Step)(0),                    // 0: caesar.v1.Step
+	(*TextRequest)(nil),          // 1: caesar.v1.TextRequest
+	(*TextResponse)(nil),         // 2: caesar.v1.TextResponse
+	(*CrackRequest)(nil),         // 3: caesar.v1.CrackRequest
+	(*Candidate)(nil),            // 4: caesar.v1.Candidate
+	(*CrackResponse)(nil),        // 5: caesar.v1.CrackResponse
+	(*RunChallengeRequest)(nil),  // 6: caesar.v1.RunChallengeRequest
+	(*RunChallengeProgress)(nil), // 7: caesar.v1.RunChallengeProgress
+}
+var file_cipher_proto_depIdxs = []int32{
+	4, // 0: caesar.v1.CrackResponse.candidates:type_name -> caesar.v1.Candidate
+	0, // 1: caesar.v1.RunChallengeProgress.step:type_name -> caesar.v1.Step
+	1, // 2: caesar.v1.Cipher.Encrypt:input_type -> caesar.v1.TextRequest
+	1, // 3: caesar.v1.Cipher.Decrypt:input_type -> caesar.v1.TextRequest
+	3, // 4: caesar.v1.Cipher.Crack:input_type -> caesar.v1.CrackRequest
+	6, // 5: caesar.v1.Cipher.RunChallenge:input_type -> caesar.v1.RunChallengeRequest
+	2, // 6: caesar.v1.Cipher.Encrypt:output_type -> caesar.v1.TextResponse
+	2, // 7: caesar.v1.Cipher.Decrypt:output_type -> caesar.v1.TextResponse
+	5, // 8: caesar.v1.Cipher.Crack:output_type -> caesar.v1.CrackResponse
+	7, // 9: caesar.v1.Cipher.RunChallenge:output_type -> caesar.v1.RunChallengeProgress
+	6, // [6:10] is the sub-list for method output_type
+	2, // [2:6] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_cipher_proto_init() }
+func file_cipher_proto_init() {
+	if File_cipher_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_cipher_proto_rawDesc), len(file_cipher_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_cipher_proto_goTypes,
+		DependencyIndexes: file_cipher_proto_depIdxs,
+		EnumInfos:         file_cipher_proto_enumTypes,
+		MessageInfos:      file_cipher_proto_msgTypes,
+	}.Build()
+	File_cipher_proto = out.File
+	file_cipher_proto_goTypes = nil
+	file_cipher_proto_depIdxs = nil
+}
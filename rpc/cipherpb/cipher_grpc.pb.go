@@ -0,0 +1,251 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             v5.28.3
+// source: cipher.proto
+
+package cipherpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Cipher_Encrypt_FullMethodName      = "/caesar.v1.Cipher/Encrypt"
+	Cipher_Decrypt_FullMethodName      = "/caesar.v1.Cipher/Decrypt"
+	Cipher_Crack_FullMethodName        = "/caesar.v1.Cipher/Crack"
+	Cipher_RunChallenge_FullMethodName = "/caesar.v1.Cipher/RunChallenge"
+)
+
+// CipherClient is the client API for Cipher service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Cipher exposes the crypto package and the challenge pipeline
+type CipherClient interface {
+	// Encrypt shifts every letter of text forward by places, which must not be zero
+	Encrypt(ctx context.Context, in *TextRequest, opts ...grpc.CallOption) (*TextResponse, error)
+	// Decrypt shifts every letter of text back by places, cracking the shift when places is zero
+	Decrypt(ctx context.Context, in *TextRequest, opts ...grpc.CallOption) (*TextResponse, error)
+	// Crack ranks every shift of text by English letter frequencies, best first
+	Crack(ctx context.Context, in *CrackRequest, opts ...grpc.CallOption) (*CrackResponse, error)
+	// RunChallenge fetches, decrypts, verifies and submits a challenge, reporting each completed step
+	RunChallenge(ctx context.Context, in *RunChallengeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[RunChallengeProgress], error)
+}
+
+type cipherClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCipherClient(cc grpc.ClientConnInterface) CipherClient {
+	return &cipherClient{cc}
+}
+
+func (c *cipherClient) Encrypt(ctx context.Context, in *TextRequest, opts ...grpc.CallOption) (*TextResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TextResponse)
+	err := c.cc.Invoke(ctx, Cipher_Encrypt_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cipherClient) Decrypt(ctx context.Context, in *TextRequest, opts ...grpc.CallOption) (*TextResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TextResponse)
+	err := c.cc.Invoke(ctx, Cipher_Decrypt_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cipherClient) Crack(ctx context.Context, in *CrackRequest, opts ...grpc.CallOption) (*CrackResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CrackResponse)
+	err := c.cc.Invoke(ctx, Cipher_Crack_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cipherClient) RunChallenge(ctx context.Context, in *RunChallengeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[RunChallengeProgress], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Cipher_ServiceDesc.Streams[0], Cipher_RunChallenge_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[RunChallengeRequest, RunChallengeProgress]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Cipher_RunChallengeClient = grpc.ServerStreamingClient[RunChallengeProgress]
+
+// CipherServer is the server API for Cipher service.
+// All implementations must embed UnimplementedCipherServer
+// for forward compatibility.
+//
+// Cipher exposes the crypto package and the challenge pipeline
+type CipherServer interface {
+	// Encrypt shifts every letter of text forward by places, which must not be zero
+	Encrypt(context.Context, *TextRequest) (*TextResponse, error)
+	// Decrypt shifts every letter of text back by places, cracking the shift when places is zero
+	Decrypt(context.Context, *TextRequest) (*TextResponse, error)
+	// Crack ranks every shift of text by English letter frequencies, best first
+	Crack(context.Context, *CrackRequest) (*CrackResponse, error)
+	// RunChallenge fetches, decrypts, verifies and submits a challenge, reporting each completed step
+	RunChallenge(*RunChallengeRequest, grpc.ServerStreamingServer[RunChallengeProgress]) error
+	mustEmbedUnimplementedCipherServer()
+}
+
+// UnimplementedCipherServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedCipherServer struct{}
+
+func (UnimplementedCipherServer) Encrypt(context.Context, *TextRequest) (*TextResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Encrypt not implemented")
+}
+func (UnimplementedCipherServer) Decrypt(context.Context, *TextRequest) (*TextResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Decrypt not implemented")
+}
+func (UnimplementedCipherServer) Crack(context.Context, *CrackRequest) (*CrackResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Crack not implemented")
+}
+func (UnimplementedCipherServer) RunChallenge(*RunChallengeRequest, grpc.ServerStreamingServer[RunChallengeProgress]) error {
+	return status.Error(codes.Unimplemented, "method RunChallenge not implemented")
+}
+func (UnimplementedCipherServer) mustEmbedUnimplementedCipherServer() {}
+func (UnimplementedCipherServer) testEmbeddedByValue()                {}
+
+// UnsafeCipherServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CipherServer will
+// result in compilation errors.
+type UnsafeCipherServer interface {
+	mustEmbedUnimplementedCipherServer()
+}
+
+func RegisterCipherServer(s grpc.ServiceRegistrar, srv CipherServer) {
+	// If the following call panics, it indicates UnimplementedCipherServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Cipher_ServiceDesc, srv)
+}
+
+func _Cipher_Encrypt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TextRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CipherServer).Encrypt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cipher_Encrypt_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CipherServer).Encrypt(ctx, req.(*TextRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cipher_Decrypt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TextRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CipherServer).Decrypt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cipher_Decrypt_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CipherServer).Decrypt(ctx, req.(*TextRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cipher_Crack_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CrackRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CipherServer).Crack(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cipher_Crack_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CipherServer).Crack(ctx, req.(*CrackRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cipher_RunChallenge_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(RunChallengeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CipherServer).RunChallenge(m, &grpc.GenericServerStream[RunChallengeRequest, RunChallengeProgress]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Cipher_RunChallengeServer = grpc.ServerStreamingServer[RunChallengeProgress]
+
+// Cipher_ServiceDesc is the grpc.ServiceDesc for Cipher service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Cipher_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "caesar.v1.Cipher",
+	HandlerType: (*CipherServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Encrypt",
+			Handler:    _Cipher_Encrypt_Handler,
+		},
+		{
+			MethodName: "Decrypt",
+			Handler:    _Cipher_Decrypt_Handler,
+		},
+		{
+			MethodName: "Crack",
+			Handler:    _Cipher_Crack_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "RunChallenge",
+			Handler:       _Cipher_RunChallenge_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "cipher.proto",
+}
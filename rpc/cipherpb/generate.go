@@ -0,0 +1,4 @@
+// Package cipherpb holds the protobuf messages and gRPC stubs generated from cipher.proto
+package cipherpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative cipher.proto
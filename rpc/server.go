@@ -0,0 +1,210 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/wesleyholiveira/caesar-challenge/crypto"
+	"github.com/wesleyholiveira/caesar-challenge/format"
+	"github.com/wesleyholiveira/caesar-challenge/history"
+	"github.com/wesleyholiveira/caesar-challenge/metrics"
+	"github.com/wesleyholiveira/caesar-challenge/rpc/cipherpb"
+	"github.com/wesleyholiveira/caesar-challenge/runner"
+	"github.com/wesleyholiveira/caesar-challenge/verify"
+)
+
+var steps = map[runner.Step]cipherpb.Step{
+	runner.Fetch:   cipherpb.Step_STEP_FETCH,
+	runner.Decrypt: cipherpb.Step_STEP_DECRYPT,
+	runner.Verify:  cipherpb.Step_STEP_VERIFY,
+	runner.Submit:  cipherpb.Step_STEP_SUBMIT,
+	runner.DryRun:  cipherpb.Step_STEP_DRY_RUN,
+}
+
+// Server implements the Cipher gRPC service on top of the crypto and runner packages
+type Server struct {
+	cipherpb.UnimplementedCipherServer
+
+	// AnswerFile is where RunChallenge keeps the challenge, clients cannot choose it
+	AnswerFile string
+	// AnswerFormat is the format of AnswerFile, see runner.Options
+	AnswerFormat format.Format
+	// GetRequest and PostRequest default to the request package http calls when nil
+	GetRequest  func(string) ([]byte, error)
+	PostRequest func(string, *bytes.Buffer) ([]byte, error)
+	// History records the runs when set
+	History *history.Store
+	// Logger is passed to every run, see runner.Options
+	Logger *slog.Logger
+
+	// mu serializes runs since they share the answer file
+	mu sync.Mutex
+}
+
+// New returns a Server running challenges in file
+func New(file string) *Server {
+	return &Server{AnswerFile: file}
+}
+
+// Register adds the service to a gRPC server
+func (s *Server) Register(g *grpc.Server) {
+	cipherpb.RegisterCipherServer(g, s)
+}
+
+// Serve registers the service on a new gRPC server and serves l until ctx is done,
+// then stops gracefully
+func (s *Server) Serve(ctx context.Context, l net.Listener, opts ...grpc.ServerOption) error {
+	g := grpc.NewServer(opts...)
+	s.Register(g)
+
+	go func() {
+		<-ctx.Done()
+		g.GracefulStop()
+	}()
+
+	return g.Serve(l)
+}
+
+func (s *Server) Encrypt(ctx context.Context, req *cipherpb.TextRequest) (*cipherpb.TextResponse, error) {
+	if err := requireText(req.GetText()); err != nil {
+		return nil, err
+	}
+
+	if req.GetPlaces() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "places is required and must not be zero")
+	}
+
+	return &cipherpb.TextResponse{
+		Text:   crypto.EncryptText(req.GetText(), int(req.GetPlaces())),
+		Places: req.GetPlaces(),
+	}, nil
+}
+
+func (s *Server) Decrypt(ctx context.Context, req *cipherpb.TextRequest) (*cipherpb.TextResponse, error) {
+	if err := requireText(req.GetText()); err != nil {
+		return nil, err
+	}
+
+	places := int(req.GetPlaces())
+	if places == 0 {
+		candidates := crypto.Crack(req.GetText())
+		metrics.ObserveCrack(len(candidates))
+		places = candidates[0].Places
+	}
+
+	metrics.ObserveDecrypt(req.GetText())
+	plain := crypto.DecryptText(req.GetText(), places)
+	return &cipherpb.TextResponse{Text: plain, Places: int32(places), Summary: crypto.Summary(plain)}, nil
+}
+
+func (s *Server) Crack(ctx context.Context, req *cipherpb.CrackRequest) (*cipherpb.CrackResponse, error) {
+	if err := requireText(req.GetText()); err != nil {
+		return nil, err
+	}
+
+	if req.GetTop() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "top must not be negative")
+	}
+
+	candidates := crypto.Crack(req.GetText())
+	metrics.ObserveCrack(len(candidates))
+	if top := int(req.GetTop()); top > 0 && top < len(candidates) {
+		candidates = candidates[:top]
+	}
+
+	resp := &cipherpb.CrackResponse{Candidates: make([]*cipherpb.Candidate, len(candidates))}
+	for i, c := range candidates {
+		resp.Candidates[i] = &cipherpb.Candidate{Places: int32(c.Places), Text: c.Text, Score: c.Score}
+	}
+
+	return resp, nil
+}
+
+func (s *Server) RunChallenge(req *cipherpb.RunChallengeRequest, stream grpc.ServerStreamingServer[cipherpb.RunChallengeProgress]) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var sendErr error
+	_, err := runner.RunContext(stream.Context(), runner.Options{
+		File:        s.AnswerFile,
+		Format:      s.AnswerFormat,
+		Force:       req.GetForce(),
+		Load:        req.GetLoad(),
+		DryRun:      req.GetDryRun(),
+		Fresh:       req.GetFresh(),
+		Resubmit:    req.GetResubmit(),
+		GetRequest:  s.GetRequest,
+		PostRequest: s.PostRequest,
+		History:     s.History,
+		Logger:      s.Logger,
+		Progress: func(step runner.Step, result *runner.Result) {
+			if sendErr == nil {
+				sendErr = stream.Send(progress(step, result))
+			}
+		},
+	})
+
+	if sendErr != nil {
+		return sendErr
+	}
+
+	return statusError(err)
+}
+
+// requireText rejects an empty text like the HTTP service does
+func requireText(text string) error {
+	if text == "" {
+		return status.Error(codes.InvalidArgument, "text is required")
+	}
+
+	return nil
+}
+
+// progress converts the result of a completed step into a stream message
+func progress(step runner.Step, result *runner.Result) *cipherpb.RunChallengeProgress {
+	p := &cipherpb.RunChallengeProgress{Step: steps[step]}
+
+	if result.Answer != nil {
+		p.CryptedText = result.Answer.CryptedText
+		p.DecryptedText = result.Answer.DecryptedText
+		p.Summary = result.Answer.SummaryCrypto
+	}
+
+	if result.Report != nil {
+		for _, d := range result.Report.Diffs {
+			p.Diffs = append(p.Diffs, d.String())
+		}
+	}
+
+	if result.Payload != nil {
+		p.Payload = result.Payload.Masked()
+	}
+
+	p.Response = result.Response
+	return p
+}
+
+// statusError maps runner errors to gRPC status codes
+func statusError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var stepErr *runner.StepError
+	switch {
+	case errors.Is(err, verify.ErrMismatch), errors.Is(err, runner.ErrSubmitUncertain), errors.Is(err, runner.ErrAlreadySubmitted):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.As(err, &stepErr) && (stepErr.Step == runner.Fetch || stepErr.Step == runner.Submit):
+		return status.Error(codes.Unavailable, err.Error())
+	}
+
+	return status.Error(codes.Internal, err.Error())
+}
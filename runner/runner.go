@@ -0,0 +1,301 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/wesleyholiveira/caesar-challenge/crypto"
+	"github.com/wesleyholiveira/caesar-challenge/format"
+	"github.com/wesleyholiveira/caesar-challenge/history"
+	"github.com/wesleyholiveira/caesar-challenge/logging"
+	"github.com/wesleyholiveira/caesar-challenge/metrics"
+	"github.com/wesleyholiveira/caesar-challenge/model"
+	"github.com/wesleyholiveira/caesar-challenge/reader"
+	"github.com/wesleyholiveira/caesar-challenge/request"
+	"github.com/wesleyholiveira/caesar-challenge/tracing"
+	"github.com/wesleyholiveira/caesar-challenge/verify"
+	"github.com/wesleyholiveira/caesar-challenge/writer"
+)
+
+// Step is a stage of the challenge pipeline
+type Step string
+
+const (
+	Fetch   Step = "fetch"
+	Decrypt Step = "decrypt"
+	Verify  Step = "verify"
+	Submit  Step = "submit"
+	// DryRun replaces Submit on dry runs, once the payload is built
+	DryRun Step = "dry-run"
+)
+
+// StepError reports the step of the pipeline that failed
+type StepError struct {
+	Step Step
+	Err  error
+}
+
+func (e *StepError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Step, e.Err)
+}
+
+func (e *StepError) Unwrap() error {
+	return e.Err
+}
+
+// Options configures a pipeline run, nil functions fall back to the request defaults
+type Options struct {
+	File string
+	// Format of File, defaults to config.AnswerFormat or the one matching its extension when nil
+	Format format.Format
+	Force  bool
+	// Load decrypts the challenge already held by File instead of fetching a new one
+	Load bool
+	// DryRun stops before submitting, the payload that would be sent is kept in Result.Payload
+	DryRun bool
+	// Fresh ignores the state of an interrupted run and fetches a new challenge
+	Fresh bool
+	// Resubmit allows posting again an answer that was submitted, or whose submit was interrupted
+	Resubmit bool
+	// Progress is called after each completed step with the result so far
+	Progress    func(Step, *Result)
+	GetRequest  func(string) ([]byte, error)
+	PostRequest func(string, *bytes.Buffer) ([]byte, error)
+	// History records every completed or failed step of the run when set
+	History *history.Store
+	// RunID identifies the run in History, a new one is generated when empty
+	RunID string
+	// Logger defaults to logging.Default, every line logged during the run carries its ID
+	// under logging.RunIDKey. The default request functions and the answer writer use it.
+	Logger *slog.Logger
+}
+
+// Result holds what each step of the pipeline produced
+type Result struct {
+	RunID string
+	// Resumed is the last step completed by the interrupted run this one resumed, if any
+	Resumed  Step
+	Answer   *model.ChallengeResponse
+	Report   *verify.Report
+	Payload  *request.SubmitPayload
+	Response []byte
+}
+
+// record appends the step to the history, if any
+func (o *Options) record(step Step, result *Result, stepErr error) error {
+	if o.History == nil {
+		return nil
+	}
+
+	e := history.Event{RunID: result.RunID, Step: string(step), Answer: result.Answer, Response: string(result.Response)}
+	if stepErr != nil {
+		e.Error = stepErr.Error()
+	}
+
+	if err := o.History.Append(e); err != nil {
+		return fmt.Errorf("history: %w", err)
+	}
+
+	return nil
+}
+
+// done records the completed step and reports it to Progress
+func (o *Options) done(step Step, result *Result) error {
+	if err := o.record(step, result, nil); err != nil {
+		return err
+	}
+
+	o.Logger.Debug("step done", "step", step)
+	if o.Progress != nil {
+		o.Progress(step, result)
+	}
+
+	return nil
+}
+
+// fail records the failed step and returns err as a StepError
+func (o *Options) fail(step Step, result *Result, err error) error {
+	stepErr := &StepError{step, err}
+	o.Logger.Error("step failed", "step", step, "error", err)
+	if recErr := o.record(step, result, err); recErr != nil {
+		return fmt.Errorf("%w (%v)", stepErr, recErr)
+	}
+
+	return stepErr
+}
+
+// Run fetches the challenge into opts.File, decrypts it, verifies the answer and submits it.
+// An answer that does not verify is only submitted when opts.Force is set, a dry run
+// never submits and does not fail on an unverified answer.
+//
+// The last completed step is kept in the state file of opts.File, see StateFile, so an
+// interrupted run is resumed from there instead of fetching a new challenge. A run
+// interrupted while submitting fails with ErrSubmitUncertain, even with opts.Fresh or opts.Load,
+// and loading an answer that was already submitted fails with ErrAlreadySubmitted, unless
+// opts.Resubmit is set.
+func Run(opts Options) (*Result, error) {
+	return RunContext(context.Background(), opts)
+}
+
+// RunContext is Run traced as a span of ctx, every step is a child span and the
+// default request functions are bound to ctx
+func RunContext(ctx context.Context, opts Options) (result *Result, err error) {
+	ctx, span := tracing.Start(ctx, "runner.Run", attribute.String("file", opts.File), attribute.Bool("dry_run", opts.DryRun))
+	defer func() { tracing.End(span, err) }()
+
+	result = &Result{RunID: opts.RunID}
+
+	state, err := ReadState(opts.File)
+	if err != nil {
+		return nil, err
+	}
+
+	if state != nil && state.Step == Submitting && !opts.Resubmit {
+		return nil, fmt.Errorf("%w: run %s, check the history before submitting again", ErrSubmitUncertain, state.RunID)
+	}
+
+	if state != nil && state.Step == Submit && opts.Load && !opts.Resubmit {
+		return nil, fmt.Errorf("%w: run %s", ErrAlreadySubmitted, state.RunID)
+	}
+
+	if state != nil && state.Step == Submitting {
+		// resubmitting resumes the interrupted run right before its submit
+		state.Step = Verify
+	}
+
+	if opts.Fresh || opts.Load || (state != nil && state.Step == Submit) {
+		state = nil
+	}
+
+	if state != nil {
+		result.Resumed = state.Step
+		if result.RunID == "" {
+			result.RunID = state.RunID
+		}
+	} else {
+		state = &State{}
+	}
+
+	if result.RunID == "" {
+		result.RunID = history.NewRunID()
+	}
+
+	if opts.Logger == nil {
+		opts.Logger = logging.Default()
+	}
+
+	opts.Logger = opts.Logger.With(logging.RunIDKey, result.RunID)
+	span.SetAttributes(attribute.String(logging.RunIDKey, result.RunID), attribute.String("resumed", string(result.Resumed)))
+	ctx = logging.NewContext(ctx, opts.Logger)
+
+	if result.Resumed != "" {
+		opts.Logger.Info("resuming run", "after", result.Resumed)
+	}
+
+	w, err := fetch(ctx, opts, state.completed(Fetch))
+	if err != nil && (opts.Load || state.completed(Fetch)) {
+		return nil, err
+	}
+
+	if err != nil {
+		return nil, opts.fail(Fetch, result, err)
+	}
+
+	w.Logger = opts.Logger
+	result.Answer = w.Response.(*model.ChallengeResponse)
+	if !state.completed(Fetch) {
+		if err := opts.complete(ctx, Fetch, result); err != nil {
+			return result, err
+		}
+	}
+
+	if !state.completed(Decrypt) {
+		if err := crypto.DecryptContext(ctx, w); err != nil {
+			return result, opts.fail(Decrypt, result, err)
+		}
+		metrics.ObserveDecrypt(result.Answer.CryptedText)
+
+		if err := opts.complete(ctx, Decrypt, result); err != nil {
+			return result, err
+		}
+	}
+
+	if result.Report, err = verify.FileContext(ctx, opts.File, opts.Format); err != nil {
+		return result, opts.fail(Verify, result, err)
+	}
+
+	if !result.Report.OK() && !opts.Force && !opts.DryRun {
+		return result, opts.fail(Verify, result, result.Report.Err())
+	}
+
+	if err := opts.complete(ctx, Verify, result); err != nil {
+		return result, err
+	}
+
+	if opts.DryRun {
+		if result.Payload, err = request.NewSubmitPayload(opts.File, opts.Format); err != nil {
+			return result, opts.fail(DryRun, result, err)
+		}
+
+		return result, opts.done(DryRun, result)
+	}
+
+	if err := writeState(ctx, opts.File, State{RunID: result.RunID, Step: Submitting}); err != nil {
+		return result, err
+	}
+
+	if result.Response, err = request.PostSubmitDataContext(ctx, opts.File, opts.Format, opts.PostRequest); err != nil {
+		// the request failed so the answer can be submitted again
+		if stateErr := writeState(ctx, opts.File, State{RunID: result.RunID, Step: Verify}); stateErr != nil {
+			return result, fmt.Errorf("%w (%v)", opts.fail(Submit, result, err), stateErr)
+		}
+
+		return result, opts.fail(Submit, result, err)
+	}
+
+	return result, opts.complete(ctx, Submit, result)
+}
+
+// complete saves the step as the state of the answer file and reports it as done.
+// Dry runs save no state so they are never resumed, and remove the state once they
+// fetched a new challenge over the answer of the run it described.
+func (o *Options) complete(ctx context.Context, step Step, result *Result) error {
+	if o.DryRun {
+		if step == Fetch && !o.Load {
+			if err := removeState(o.File); err != nil {
+				return err
+			}
+		}
+
+		return o.done(step, result)
+	}
+
+	if err := writeState(ctx, o.File, State{RunID: result.RunID, Step: step}); err != nil {
+		return err
+	}
+
+	return o.done(step, result)
+}
+
+// fetch gets a new challenge, or reads the one held by opts.File when opts.Load is set
+// or the challenge was already fetched
+func fetch(ctx context.Context, opts Options, fetched bool) (*writer.WriterAnswer, error) {
+	if !opts.Load && !fetched {
+		return request.GetCryptedTextContext(ctx, opts.File, opts.Format, opts.GetRequest, nil)
+	}
+
+	response, err := reader.ReadChallengeContext(ctx, opts.File, opts.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	w := writer.New()
+	w.File = opts.File
+	w.Format = opts.Format
+	w.Response = response
+	return w, nil
+}
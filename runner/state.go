@@ -0,0 +1,89 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/wesleyholiveira/caesar-challenge/format"
+	"github.com/wesleyholiveira/caesar-challenge/writer"
+)
+
+// Submitting is recorded right before the answer is posted, a run found in this
+// state was interrupted while submitting and may or may not have been received
+const Submitting Step = "submitting"
+
+// StateSuffix is appended to the answer file name to get its state file
+const StateSuffix = ".state"
+
+// ErrSubmitUncertain is returned when resuming a run that was interrupted while submitting
+var ErrSubmitUncertain = errors.New("previous run was interrupted while submitting, it may already have been received")
+
+// ErrAlreadySubmitted is returned when loading an answer that was already submitted
+var ErrAlreadySubmitted = errors.New("answer was already submitted")
+
+// State is the last completed step of the run held by an answer file
+type State struct {
+	RunID   string    `json:"run_id"`
+	Step    Step      `json:"step"`
+	Updated time.Time `json:"updated"`
+}
+
+// StateFile returns the state file kept alongside the answer file
+func StateFile(file string) string {
+	return file + StateSuffix
+}
+
+// ReadState returns the state of the answer file, or nil when there is none
+func ReadState(file string) (*State, error) {
+	data, err := os.ReadFile(StateFile(file))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	state := &State{}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("%s: %v", StateFile(file), err)
+	}
+
+	return state, nil
+}
+
+// WriteState atomically replaces the state of the answer file
+func WriteState(file string, state State) error {
+	return writeState(context.Background(), file, state)
+}
+
+// removeState removes the state of the answer file, if any
+func removeState(file string) error {
+	if err := os.Remove(StateFile(file)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+func writeState(ctx context.Context, file string, state State) error {
+	if state.Updated.IsZero() {
+		state.Updated = time.Now().UTC()
+	}
+
+	w := writer.New()
+	w.File = StateFile(file)
+	w.Format = format.JSON
+	w.Response = state
+	return writer.WriteAnswerContext(ctx, w)
+}
+
+// completed reports whether the state has gone past step
+func (s *State) completed(step Step) bool {
+	order := map[Step]int{Fetch: 1, Decrypt: 2, Verify: 3, Submitting: 4, Submit: 5}
+	return order[s.Step] >= order[step]
+}
@@ -0,0 +1,231 @@
+package server
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/wesleyholiveira/caesar-challenge/crypto"
+	"github.com/wesleyholiveira/caesar-challenge/metrics"
+)
+
+// Defaults used when the matching Server fields are not set
+const (
+	DefaultMaxBodyBytes    = 1 << 20
+	DefaultShutdownTimeout = 10 * time.Second
+)
+
+//go:embed openapi.json
+var openAPI []byte
+
+// Server exposes the crypto package over HTTP with JSON endpoints
+type Server struct {
+	// MaxBodyBytes limits the size of request bodies, defaults to DefaultMaxBodyBytes
+	MaxBodyBytes int64
+	// ShutdownTimeout bounds how long in-flight requests may take once the context is done,
+	// defaults to DefaultShutdownTimeout
+	ShutdownTimeout time.Duration
+	ready           atomic.Bool
+}
+
+// New returns a Server with the default limits
+func New() *Server {
+	return &Server{MaxBodyBytes: DefaultMaxBodyBytes, ShutdownTimeout: DefaultShutdownTimeout}
+}
+
+// TextRequest is the body of the encrypt, decrypt, crack and digest endpoints
+type TextRequest struct {
+	Text   string `json:"text"`
+	Places int    `json:"places,omitempty"`
+	Top    int    `json:"top,omitempty"`
+}
+
+// TextResponse is the reply of the encrypt, decrypt and digest endpoints
+type TextResponse struct {
+	Text    string `json:"text,omitempty"`
+	Places  int    `json:"places,omitempty"`
+	Summary string `json:"summary,omitempty"`
+}
+
+// Candidate is a ranked decryption returned by the crack endpoint
+type Candidate struct {
+	Places int     `json:"places"`
+	Text   string  `json:"text"`
+	Score  float64 `json:"score"`
+}
+
+// CrackResponse is the reply of the crack endpoint
+type CrackResponse struct {
+	Candidates []Candidate `json:"candidates"`
+}
+
+// ErrorResponse is the body of every failed request
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// Handler returns the routes of the service
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/encrypt", s.text(func(req TextRequest) (interface{}, error) {
+		if req.Places == 0 {
+			return nil, errors.New("places is required and must not be zero")
+		}
+
+		return TextResponse{Text: crypto.EncryptText(req.Text, req.Places), Places: req.Places}, nil
+	}))
+	mux.HandleFunc("POST /v1/decrypt", s.text(func(req TextRequest) (interface{}, error) {
+		places := req.Places
+		if places == 0 {
+			candidates := crypto.Crack(req.Text)
+			metrics.ObserveCrack(len(candidates))
+			places = candidates[0].Places
+		}
+
+		metrics.ObserveDecrypt(req.Text)
+		plain := crypto.DecryptText(req.Text, places)
+		return TextResponse{Text: plain, Places: places, Summary: crypto.Summary(plain)}, nil
+	}))
+	mux.HandleFunc("POST /v1/crack", s.text(func(req TextRequest) (interface{}, error) {
+		if req.Top < 0 {
+			return nil, errors.New("top must not be negative")
+		}
+
+		candidates := crypto.Crack(req.Text)
+		metrics.ObserveCrack(len(candidates))
+		if req.Top > 0 && req.Top < len(candidates) {
+			candidates = candidates[:req.Top]
+		}
+
+		resp := CrackResponse{Candidates: make([]Candidate, len(candidates))}
+		for i, c := range candidates {
+			resp.Candidates[i] = Candidate{Places: c.Places, Text: c.Text, Score: c.Score}
+		}
+
+		return resp, nil
+	}))
+	mux.HandleFunc("POST /v1/digest", s.text(func(req TextRequest) (interface{}, error) {
+		return TextResponse{Summary: crypto.Summary(req.Text)}, nil
+	}))
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+	mux.HandleFunc("GET /readyz", func(w http.ResponseWriter, r *http.Request) {
+		if !s.ready.Load() {
+			writeJSON(w, http.StatusServiceUnavailable, map[string]string{"status": "unavailable"})
+			return
+		}
+
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ready"})
+	})
+	mux.HandleFunc("GET /openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(openAPI)
+	})
+
+	return mux
+}
+
+// text decodes a TextRequest, bounded by MaxBodyBytes, and writes the JSON result of handle
+func (s *Server) text(handle func(TextRequest) (interface{}, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		limit := s.MaxBodyBytes
+		if limit <= 0 {
+			limit = DefaultMaxBodyBytes
+		}
+
+		// the outer Text shadows the one of TextRequest to tell a missing text from an empty one
+		body := struct {
+			TextRequest
+			Text *string `json:"text"`
+		}{}
+		decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, limit))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&body); err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				writeJSON(w, http.StatusRequestEntityTooLarge, ErrorResponse{fmt.Sprintf("body exceeds %d bytes", limit)})
+				return
+			}
+
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{err.Error()})
+			return
+		}
+
+		if body.Text == nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{"text is required"})
+			return
+		}
+
+		req := body.TextRequest
+		req.Text = *body.Text
+		resp, err := handle(req)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{err.Error()})
+			return
+		}
+
+		writeJSON(w, http.StatusOK, resp)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// Serve accepts connections on l until ctx is done, then stops being ready and
+// waits up to ShutdownTimeout for in-flight requests before returning
+func (s *Server) Serve(ctx context.Context, l net.Listener) error {
+	srv := &http.Server{Handler: s.Handler(), ReadHeaderTimeout: 10 * time.Second}
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- srv.Serve(l)
+	}()
+
+	s.ready.Store(true)
+
+	select {
+	case err := <-errc:
+		s.ready.Store(false)
+		return err
+	case <-ctx.Done():
+	}
+
+	s.ready.Store(false)
+	timeout := s.ShutdownTimeout
+	if timeout <= 0 {
+		timeout = DefaultShutdownTimeout
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+
+	if err := <-errc; err != http.ErrServerClosed {
+		return err
+	}
+
+	return nil
+}
+
+// ListenAndServe listens on addr and serves until ctx is done, see Serve
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	return s.Serve(ctx, l)
+}
@@ -0,0 +1,87 @@
+package analysis
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wesleyholiveira/caesar-challenge/analysis"
+	"github.com/wesleyholiveira/caesar-challenge/crypto"
+	"github.com/wesleyholiveira/caesar-challenge/langmodel"
+)
+
+const plaintext = "it was a bright cold day in april and the clocks were striking thirteen while the wind swept " +
+	"little eddies of dust and torn paper along the street and into the open doors of the houses where " +
+	"the families were sitting down to their evening meals after a long day of work in the fields"
+
+// vigenere encrypts the letters of text with the repeating key
+func vigenere(text, key string) string {
+	var b strings.Builder
+	i := 0
+	for _, char := range text {
+		if char >= 'a' && char <= 'z' {
+			char = 'a' + (char-'a'+rune(key[i%len(key)]-'a'))%26
+			i++
+		}
+		b.WriteRune(char)
+	}
+
+	return b.String()
+}
+
+// columns writes the letters of text in rows of n and reads them back column by column
+func columns(text string, n int) string {
+	letters := langmodel.Letters(text)
+	var b strings.Builder
+	for col := 0; col < n; col++ {
+		for i := col; i < len(letters); i += n {
+			b.WriteByte(letters[i])
+		}
+	}
+
+	return b.String()
+}
+
+func TestAnalyzeFamily(t *testing.T) {
+	en, _ := langmodel.Builtin(langmodel.English)
+
+	assert.Equal(t, analysis.Plaintext, analysis.Analyze(plaintext, en).Family)
+	assert.Equal(t, analysis.Monoalphabetic, analysis.Analyze(crypto.EncryptText(plaintext, 5), en).Family)
+	assert.Equal(t, analysis.Polyalphabetic, analysis.Analyze(vigenere(plaintext, "lemon"), en).Family)
+	assert.Equal(t, analysis.Transposition, analysis.Analyze(columns(plaintext, 7), en).Family)
+	assert.Equal(t, analysis.Unknown, analysis.Analyze("khoor", en).Family)
+}
+
+func TestAnalyzeStatistics(t *testing.T) {
+	en, _ := langmodel.Builtin(langmodel.English)
+	r := analysis.Analyze("Aab, 1!", en)
+
+	assert.Equal(t, 7, r.Length)
+	assert.Equal(t, 3, r.Letters)
+	assert.Equal(t, "a", r.Frequencies[0].Letter)
+	assert.Equal(t, 2, r.Frequencies[0].Count)
+	assert.InDelta(t, 66.67, r.Frequencies[0].Percent, 0.01)
+	assert.InDelta(t, 8, r.Frequencies[0].Expected, 2)
+	assert.InDelta(t, 1.0/3, r.IndexOfCoincidence, 1e-9)
+	assert.InDelta(t, 0.918, r.Entropy, 0.001)
+
+	assert.Equal(t, 0.0, analysis.Analyze("", en).IndexOfCoincidence)
+	assert.Equal(t, 0.0, analysis.Analyze("a", en).IndexOfCoincidence)
+}
+
+func TestAnalyzeRepeats(t *testing.T) {
+	en, _ := langmodel.Builtin(langmodel.English)
+	r := analysis.Analyze("the xy abc d the w abc r the", en)
+
+	assert.Equal(t, []analysis.Repeat{
+		{NGram: "the", Count: 3, Positions: []int{0, 9, 17}, Spacings: []int{9, 8}},
+		{NGram: "abc", Count: 2, Positions: []int{5, 13}, Spacings: []int{8}},
+	}, r.Repeats)
+
+	r = analysis.Analyze(vigenere(plaintext, "lemon"), en)
+	for _, rep := range r.Repeats[:1] {
+		for _, spacing := range rep.Spacings {
+			assert.Zero(t, spacing%5, "%s spacing %d", rep.NGram, spacing)
+		}
+	}
+}
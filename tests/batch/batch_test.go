@@ -0,0 +1,112 @@
+package batch
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wesleyholiveira/caesar-challenge/batch"
+	"github.com/wesleyholiveira/caesar-challenge/crypto"
+)
+
+func process(t *testing.T, input, inKind, outKind string, workers int) (string, batch.Stats) {
+	dec, err := batch.NewDecoder(strings.NewReader(input), inKind)
+	assert.NoError(t, err)
+
+	out := new(bytes.Buffer)
+	enc, err := batch.NewEncoder(out, outKind)
+	assert.NoError(t, err)
+
+	stats, err := batch.Process(dec, workers, enc.Encode)
+	assert.NoError(t, err)
+	assert.NoError(t, enc.Flush())
+	return out.String(), stats
+}
+
+func TestProcessJSONL(t *testing.T) {
+	input := `{"id":"a","cifrado":"wkh txlfn eurzq ira mxpsv ryhu wkh odcb grj.","numero_casas":3}
+
+{"cifrado":"wkh txlfn eurzq ira mxpsv ryhu wkh odcb grj."}
+not json
+{"cifrado":""}
+{"cifrado":"abc","numero_casas":40}
+`
+	out, stats := process(t, input, batch.JSONL, batch.JSONL, 4)
+	assert.Equal(t, batch.Stats{Items: 5, Failed: 3}, stats)
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	assert.Len(t, lines, 5)
+	digest := crypto.Summary("the quick brown fox jumps over the lazy dog.")
+	assert.Equal(t, `{"linha":1,"id":"a","numero_casas":3,"cifrado":"wkh txlfn eurzq ira mxpsv ryhu wkh odcb grj.","decifrado":"the quick brown fox jumps over the lazy dog.","resumo_criptografico":"`+digest+`"}`, lines[0])
+	assert.Contains(t, lines[1], `"linha":3,"numero_casas":3`)
+	assert.Contains(t, lines[2], `"linha":4`)
+	assert.Contains(t, lines[2], `"error":"invalid character`)
+	assert.Contains(t, lines[3], `"error":"cifrado is required"`)
+	assert.Contains(t, lines[4], `out of range`)
+}
+
+func TestProcessCSV(t *testing.T) {
+	input := "cifrado,numero_casas\nkhoor,3\nkhoor,x\n"
+	out, stats := process(t, input, batch.CSV, batch.CSV, 1)
+	assert.Equal(t, batch.Stats{Items: 2, Failed: 1}, stats)
+	assert.Equal(t, "linha,id,numero_casas,cifrado,decifrado,resumo_criptografico,error\n"+
+		"2,,3,khoor,hello,"+crypto.Summary("hello")+",\n"+
+		"3,,,khoor,,,\"numero_casas: strconv.Atoi: parsing \"\"x\"\": invalid syntax\"\n", out)
+
+	_, err := batch.NewDecoder(strings.NewReader(""), "xml")
+	assert.Error(t, err)
+
+	dec, _ := batch.NewDecoder(strings.NewReader("text\nabc\n"), batch.CSV)
+	_, err = dec.Next()
+	assert.Error(t, err)
+}
+
+func TestProcessCSVMalformedRow(t *testing.T) {
+	input := "cifrado\nabc\n\"bad\"x\nbcd\n"
+	out, stats := process(t, input, batch.CSV, batch.JSONL, 2)
+	assert.Equal(t, batch.Stats{Items: 3, Failed: 1}, stats)
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	assert.Len(t, lines, 3)
+	assert.Contains(t, lines[0], `"linha":2`)
+	assert.Contains(t, lines[1], `"linha":3`)
+	assert.Contains(t, lines[1], `"error":"parse error on line 3`)
+	assert.Contains(t, lines[2], `"linha":4`)
+	assert.NotContains(t, lines[2], `"error"`)
+}
+
+func TestProcessKeepsOrder(t *testing.T) {
+	input := new(strings.Builder)
+	for i := 0; i < 500; i++ {
+		fmt.Fprintf(input, "{\"id\":\"%d\",\"cifrado\":%q,\"numero_casas\":%d}\n", i, crypto.EncryptText("order matters", i%25+1), i%25+1)
+	}
+
+	dec, _ := batch.NewDecoder(strings.NewReader(input.String()), batch.JSONL)
+	ids := []string{}
+	stats, err := batch.Process(dec, 16, func(r batch.Result) error {
+		assert.Equal(t, "order matters", r.DecryptedText)
+		ids = append(ids, r.ID)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 500, stats.Items)
+	for i, id := range ids {
+		assert.Equal(t, fmt.Sprint(i), id)
+	}
+
+	dec, _ = batch.NewDecoder(strings.NewReader(input.String()), batch.JSONL)
+	stop := errors.New("stop")
+	stats, err = batch.Process(dec, 4, func(r batch.Result) error { return stop })
+	assert.Equal(t, stop, err)
+	assert.Equal(t, 1, stats.Items)
+}
+
+func TestKindForFile(t *testing.T) {
+	assert.Equal(t, batch.CSV, batch.KindForFile("items.CSV"))
+	assert.Equal(t, batch.CSV, batch.KindForFile("items.csv.gz"))
+	assert.Equal(t, batch.JSONL, batch.KindForFile("items.jsonl"))
+	assert.Equal(t, batch.JSONL, batch.KindForFile("-"))
+}
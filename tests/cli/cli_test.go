@@ -0,0 +1,569 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wesleyholiveira/caesar-challenge/cli"
+	"github.com/wesleyholiveira/caesar-challenge/config"
+	"github.com/wesleyholiveira/caesar-challenge/crypto"
+)
+
+const challenge = `{"numero_casas":3,"token":"token","cifrado":"wkh txlfn eurzq ira mxpsv ryhu wkh odcb grj.","decifrado":"","resumo_criptografico":""}`
+
+type harness struct {
+	app       *cli.App
+	stdout    *bytes.Buffer
+	stderr    *bytes.Buffer
+	file      string
+	history   string
+	submitted []string
+}
+
+func newHarness(t *testing.T) *harness {
+	h := &harness{stdout: new(bytes.Buffer), stderr: new(bytes.Buffer)}
+	h.file = filepath.Join(t.TempDir(), "answer.json")
+	h.history = filepath.Join(t.TempDir(), "history.jsonl")
+	config.HistoryFile = h.history
+	t.Cleanup(func() { config.HistoryFile = "" })
+	h.app = &cli.App{
+		Stdin:  strings.NewReader(""),
+		Stdout: h.stdout,
+		Stderr: h.stderr,
+		GetRequest: func(string) ([]byte, error) {
+			return []byte(challenge), nil
+		},
+		PostRequest: func(url string, body *bytes.Buffer) ([]byte, error) {
+			h.submitted = append(h.submitted, body.String())
+			return []byte(`{"score":100}`), nil
+		},
+	}
+
+	return h
+}
+
+func (h *harness) run(args ...string) int {
+	h.stdout.Reset()
+	h.stderr.Reset()
+	return h.app.Run(args)
+}
+
+func TestUsage(t *testing.T) {
+	h := newHarness(t)
+	assert.Equal(t, cli.ExitUsage, h.run())
+	assert.Contains(t, h.stderr.String(), "Commands:")
+
+	assert.Equal(t, cli.ExitUsage, h.run("unknown"))
+	assert.Equal(t, cli.ExitOK, h.run("help"))
+	assert.Equal(t, cli.ExitOK, h.run("help", "crack"))
+	assert.Contains(t, h.stderr.String(), "-top")
+	assert.Equal(t, cli.ExitOK, h.run("submit", "-h"))
+	assert.Equal(t, cli.ExitUsage, h.run("fetch", "-nope"))
+	assert.Equal(t, cli.ExitUsage, h.run("fetch", "extra"))
+}
+
+func TestRun(t *testing.T) {
+	h := newHarness(t)
+	assert.Equal(t, cli.ExitOK, h.run("run", "-answer", h.file))
+	assert.Equal(t, "{\"score\":100}\n", h.stdout.String())
+	assert.Len(t, h.submitted, 1)
+	assert.Contains(t, h.submitted[0], "the quick brown fox jumps over the lazy dog.")
+
+	h.app.GetRequest = func(string) ([]byte, error) { return nil, errors.New("offline") }
+	assert.Equal(t, cli.ExitRequest, h.run("run", "-answer", h.file))
+	assert.Len(t, h.submitted, 1)
+}
+
+func TestSteps(t *testing.T) {
+	h := newHarness(t)
+	assert.Equal(t, cli.ExitOK, h.run("fetch", "-answer", h.file))
+	assert.Equal(t, cli.ExitUnverified, h.run("verify", "-answer", h.file))
+	assert.Equal(t, cli.ExitUnverified, h.run("submit", "-answer", h.file))
+	assert.Empty(t, h.submitted)
+
+	assert.Equal(t, cli.ExitOK, h.run("decrypt", "-answer", h.file))
+	assert.Equal(t, "the quick brown fox jumps over the lazy dog.\n", h.stdout.String())
+	assert.Equal(t, cli.ExitOK, h.run("verify", "-answer", h.file))
+	assert.Equal(t, cli.ExitOK, h.run("submit", "-answer", h.file))
+	assert.Len(t, h.submitted, 1)
+	assert.Equal(t, cli.ExitError, h.run("submit", "-answer", h.file))
+	assert.Contains(t, h.stderr.String(), "already submitted")
+	assert.Len(t, h.submitted, 1)
+
+	assert.Equal(t, cli.ExitOK, h.run("decrypt", "-answer", h.file, "-places", "4"))
+	assert.Equal(t, cli.ExitOK, h.run("verify", "-answer", h.file))
+
+	os.WriteFile(h.file, []byte(challenge), 0644)
+	assert.Equal(t, cli.ExitUnverified, h.run("submit", "-answer", h.file))
+	assert.Equal(t, cli.ExitOK, h.run("submit", "-answer", h.file, "-force"))
+	assert.Len(t, h.submitted, 2)
+
+	h.app.PostRequest = func(string, *bytes.Buffer) ([]byte, error) { return nil, errors.New("offline") }
+	assert.Equal(t, cli.ExitRequest, h.run("submit", "-answer", h.file, "-force"))
+
+	assert.Equal(t, cli.ExitError, h.run("verify", "-answer", filepath.Join(t.TempDir(), "missing.json")))
+}
+
+func TestCipherCommands(t *testing.T) {
+	h := newHarness(t)
+	assert.Equal(t, cli.ExitOK, h.run("encrypt", "-places", "3", "hello", "world"))
+	assert.Equal(t, "khoor zruog\n", h.stdout.String())
+
+	h.app.Stdin = strings.NewReader("hello\n")
+	assert.Equal(t, cli.ExitOK, h.run("encrypt", "-places", "1"))
+	assert.Equal(t, "ifmmp\n", h.stdout.String())
+
+	assert.Equal(t, cli.ExitOK, h.run("crack", "-top", "1", "wkh", "txlfn", "eurzq", "ira", "mxpsv", "ryhu", "wkh", "odcb", "grj"))
+	assert.Equal(t, " 3\t", h.stdout.String()[:3])
+
+	assert.Equal(t, cli.ExitUsage, h.run("crack", "-write", "text"))
+
+	assert.Equal(t, cli.ExitOK, h.run("fetch", "-answer", h.file))
+	assert.Equal(t, cli.ExitOK, h.run("crack", "-answer", h.file, "-write", "-top", "0"))
+	assert.Len(t, strings.Split(strings.TrimSpace(h.stdout.String()), "\n"), 26)
+	assert.Equal(t, cli.ExitOK, h.run("verify", "-answer", h.file))
+
+	data, _ := os.ReadFile(h.file)
+	assert.Contains(t, string(data), "the quick brown fox")
+}
+
+func TestDryRun(t *testing.T) {
+	h := newHarness(t)
+	config.TokenCodeNation = "secret-token"
+	defer func() { config.TokenCodeNation = "" }()
+	h.app.GetRequest = func(string) ([]byte, error) {
+		return []byte(strings.Replace(challenge, `"token":"token"`, `"token":"secret-token"`, 1)), nil
+	}
+
+	assert.Equal(t, cli.ExitOK, h.run("run", "-dry-run", "-answer", h.file))
+	assert.Empty(t, h.submitted)
+	assert.True(t, strings.HasPrefix(h.stdout.String(), "POST submit-solution?token=********\n\n--"))
+	assert.Contains(t, h.stdout.String(), `filename="answer.json"`)
+	assert.Contains(t, h.stdout.String(), `"token":"********"`)
+	assert.Contains(t, h.stdout.String(), "the quick brown fox jumps over the lazy dog.")
+	assert.NotContains(t, h.stdout.String(), "secret-token")
+
+	h.app.GetRequest = func(string) ([]byte, error) { return nil, errors.New("offline") }
+	os.WriteFile(h.file, []byte(challenge), 0644)
+	assert.Equal(t, cli.ExitOK, h.run("run", "-dry-run", "-load", "-answer", h.file))
+	assert.Empty(t, h.submitted)
+	assert.Contains(t, h.stdout.String(), "the quick brown fox jumps over the lazy dog.")
+
+	assert.Equal(t, cli.ExitError, h.run("run", "-dry-run", "-load", "-answer", filepath.Join(t.TempDir(), "missing.json")))
+}
+
+func TestDryRunAfterRun(t *testing.T) {
+	h := newHarness(t)
+	assert.Equal(t, cli.ExitOK, h.run("run", "-answer", h.file))
+	assert.Equal(t, cli.ExitOK, h.run("run", "-dry-run", "-answer", h.file))
+	assert.Equal(t, cli.ExitOK, h.run("submit", "-answer", h.file))
+	assert.Len(t, h.submitted, 2)
+
+	assert.Equal(t, cli.ExitOK, h.run("run", "-answer", h.file))
+	assert.Equal(t, cli.ExitOK, h.run("run", "-dry-run", "-answer", h.file))
+	assert.Equal(t, cli.ExitOK, h.run("run", "-load", "-answer", h.file))
+	assert.Len(t, h.submitted, 4)
+}
+
+func TestDecryptText(t *testing.T) {
+	h := newHarness(t)
+	assert.Equal(t, cli.ExitOK, h.run("decrypt", "-places", "1", "bcd"))
+	assert.Equal(t, "abc\na9993e364706816aba3e25717850c26c9cd0d89d\n", h.stdout.String())
+
+	assert.Equal(t, cli.ExitOK, h.run("decrypt", "-places", "1", "-output", "json", "bcd"))
+	assert.Equal(t, `{"numero_casas":1,"cifrado":"bcd","decifrado":"abc","resumo_criptografico":"a9993e364706816aba3e25717850c26c9cd0d89d"}`+"\n", h.stdout.String())
+
+	h.app.Stdin = strings.NewReader("wkh txlfn eurzq ira mxpsv ryhu wkh odcb grj.\n")
+	assert.Equal(t, cli.ExitOK, h.run("decrypt", "-in", "-"))
+	assert.True(t, strings.HasPrefix(h.stdout.String(), "the quick brown fox jumps over the lazy dog.\n"))
+
+	file := filepath.Join(t.TempDir(), "cipher.txt")
+	os.WriteFile(file, []byte("ebiil tloia"), 0644)
+	assert.Equal(t, cli.ExitOK, h.run("decrypt", "-in", file, "-places", "23"))
+	assert.True(t, strings.HasPrefix(h.stdout.String(), "hello world\n"))
+
+	// piped input is decrypted without -in and the answer file is neither read nor written
+	h.app.Stdin = strings.NewReader("wkh txlfn eurzq ira mxpsv ryhu wkh odcb grj.\n")
+	assert.Equal(t, cli.ExitOK, h.run("decrypt", "-output", "json"))
+	assert.Contains(t, h.stdout.String(), `"decifrado":"the quick brown fox jumps over the lazy dog."`)
+	_, err := os.Stat("answer.json")
+	assert.True(t, os.IsNotExist(err))
+
+	assert.Equal(t, cli.ExitOK, h.run("fetch", "-answer", h.file))
+	h.app.Stdin = strings.NewReader("khoor\n")
+	assert.Equal(t, cli.ExitOK, h.run("decrypt", "-answer", h.file))
+	assert.Equal(t, "the quick brown fox jumps over the lazy dog.\n", h.stdout.String())
+
+	assert.Equal(t, cli.ExitUsage, h.run("decrypt", "-in", file, "extra"))
+	assert.Equal(t, cli.ExitUsage, h.run("decrypt", "-output", "xml", "bcd"))
+	assert.Equal(t, cli.ExitError, h.run("decrypt", "-in", filepath.Join(t.TempDir(), "missing.txt")))
+}
+
+func TestAnswerFormat(t *testing.T) {
+	h := newHarness(t)
+	assert.Equal(t, cli.ExitOK, h.run("fetch", "-answer", h.file, "-format", "yaml"))
+	assert.Equal(t, cli.ExitOK, h.run("decrypt", "-answer", h.file, "-format", "yaml"))
+	assert.Equal(t, cli.ExitOK, h.run("verify", "-answer", h.file, "-format", "yaml"))
+	data, _ := os.ReadFile(h.file)
+	assert.Contains(t, string(data), "decifrado: the quick brown fox jumps over the lazy dog.")
+
+	// the format of one run is not kept for the next
+	assert.Equal(t, "", config.AnswerFormat)
+	assert.Equal(t, cli.ExitError, h.run("verify", "-answer", h.file))
+	assert.Equal(t, cli.ExitUsage, h.run("verify", "-answer", h.file, "-format", "xml"))
+
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "a.txt"), []byte("khoor"), 0644)
+	assert.Equal(t, cli.ExitOK, h.run("watch", "-once", "-settle", "1ns", "-format", "yaml", dir))
+	assert.Equal(t, filepath.Join(dir, "done", "a.txt.answer.yaml")+"\n", h.stdout.String())
+	os.WriteFile(filepath.Join(dir, "b.txt"), []byte("khoor"), 0644)
+	assert.Equal(t, cli.ExitOK, h.run("watch", "-once", "-settle", "1ns", dir))
+	assert.Equal(t, filepath.Join(dir, "done", "b.txt.answer.json")+"\n", h.stdout.String())
+}
+
+func TestExplore(t *testing.T) {
+	h := newHarness(t)
+	assert.Equal(t, cli.ExitOK, h.run("fetch", "-answer", h.file))
+
+	dict := filepath.Join(t.TempDir(), "words.txt")
+	os.WriteFile(dict, []byte("the\nquick\nbrown\nfox\njumps\nover\nlazy\ndog\n"), 0644)
+
+	h.app.Stdin = strings.NewReader("+\n-\n10\nl\nb\ns\n")
+	assert.Equal(t, cli.ExitOK, h.run("explore", "-color=false", "-dict", dict, "-answer", h.file))
+	out := h.stdout.String()
+	assert.Contains(t, out, "shift  3  score")
+	assert.Contains(t, out, "rank 1/26")
+	assert.Contains(t, out, "plain:  [the] [quick] [brown] [fox] [jumps] [over] [the] [lazy] [dog].")
+	assert.Contains(t, out, "shift 10")
+	assert.Contains(t, out, "*16. shift 10")
+	assert.Contains(t, out, "saved shift 3 to "+h.file)
+	assert.Equal(t, cli.ExitOK, h.run("verify", "-answer", h.file))
+
+	save := filepath.Join(t.TempDir(), "chosen.json")
+	h.app.Stdin = strings.NewReader("4\ns\n")
+	assert.Equal(t, cli.ExitOK, h.run("explore", "-save", save, "ebiil"))
+	data, _ := os.ReadFile(save)
+	assert.Contains(t, string(data), `"numero_casas":4`)
+
+	h.app.Stdin = strings.NewReader("nope\nq\n")
+	assert.Equal(t, cli.ExitOK, h.run("explore", "-save", save, "ebiil"))
+	assert.Contains(t, h.stdout.String(), `unknown command "nope"`)
+	assert.NotContains(t, h.stdout.String(), "saved")
+
+	before, _ := os.ReadFile(h.file)
+	h.app.Stdin = strings.NewReader("s\n")
+	assert.Equal(t, cli.ExitUsage, h.run("explore", "ebiil"))
+	assert.Contains(t, h.stderr.String(), "-save is required")
+	after, _ := os.ReadFile(h.file)
+	assert.Equal(t, string(before), string(after))
+
+	assert.Equal(t, cli.ExitUsage, h.run("explore", "-ui", "gui", "-answer", h.file))
+}
+
+func TestExploreTerminal(t *testing.T) {
+	h := newHarness(t)
+	assert.Equal(t, cli.ExitOK, h.run("fetch", "-answer", h.file))
+
+	// right, left, down the ranking, back to the best, a jump to 10, up the ranking and a save
+	h.app.Stdin = strings.NewReader("\x1b[C\x1b[D\x1b[Bb10\r\x1b[Ax7\x7fs")
+	assert.Equal(t, cli.ExitOK, h.run("explore", "-ui", "tui", "-color=false", "-answer", h.file))
+	out := h.stdout.String()
+	assert.True(t, strings.HasPrefix(out, "\x1b[?1049h"))
+	assert.Contains(t, out, "plain:  [the] quick brown fox jumps [over] [the] lazy dog.")
+	assert.Contains(t, out, "\x1b[7m  1. shift  3")
+	assert.Contains(t, out, "\x1b[7m 16. shift 10")
+	assert.Contains(t, out, "shift: 10")
+	assert.Contains(t, out, `unknown key "x"`)
+	assert.Contains(t, out, "shift: 7")
+	assert.Contains(t, out, "\x1b[?1049l")
+
+	frames := strings.Split(out, "\x1b[H\x1b[2J")
+	last := frames[len(frames)-1]
+	assert.Contains(t, last, "\x1b[7m 15. shift")
+	assert.Contains(t, last, "saved shift ")
+
+	data, _ := os.ReadFile(h.file)
+	assert.NotContains(t, string(data), `"numero_casas":3,`)
+
+	h.app.Stdin = strings.NewReader("\x1b")
+	assert.Equal(t, cli.ExitOK, h.run("explore", "-ui", "tui", "-answer", h.file))
+	assert.NotContains(t, h.stdout.String(), "saved")
+}
+
+func TestHistory(t *testing.T) {
+	h := newHarness(t)
+	assert.Equal(t, cli.ExitOK, h.run("run", "-answer", h.file))
+	assert.Equal(t, cli.ExitOK, h.run("run", "-dry-run", "-answer", h.file))
+
+	h.app.GetRequest = func(string) ([]byte, error) { return nil, errors.New("offline") }
+	assert.Equal(t, cli.ExitRequest, h.run("run", "-answer", h.file))
+
+	assert.Equal(t, cli.ExitOK, h.run("history", "list"))
+	lines := strings.Split(strings.TrimSpace(h.stdout.String()), "\n")
+	assert.Len(t, lines, 3)
+	assert.Contains(t, lines[0], "submit")
+	assert.Contains(t, lines[0], "\tok\t")
+	assert.Contains(t, lines[1], "dry-run")
+	assert.Contains(t, lines[2], "fetch")
+	assert.Contains(t, lines[2], "\tfailed")
+
+	id := strings.Split(lines[0], "\t")[0]
+	assert.Equal(t, cli.ExitOK, h.run("history", "show", id))
+	assert.Contains(t, h.stdout.String(), "run "+id)
+	assert.Contains(t, h.stdout.String(), `"decifrado": "the quick brown fox jumps over the lazy dog."`)
+	assert.Contains(t, h.stdout.String(), `"token": "********"`)
+	assert.Contains(t, h.stdout.String(), `response: {"score":100}`)
+
+	assert.Equal(t, cli.ExitOK, h.run("history", "resubmit", id[:20]))
+	assert.Len(t, h.submitted, 2)
+	answer := `{"numero_casas":3,"token":"token","cifrado":"wkh txlfn eurzq ira mxpsv ryhu wkh odcb grj.","decifrado":"the quick brown fox jumps over the lazy dog.","resumo_criptografico":"21d5e0a8782d7921b284f615e59460730ec4c21a"}`
+	assert.Contains(t, h.submitted[0], answer)
+	assert.Contains(t, h.submitted[1], answer)
+
+	config.AnswerFormat = "yaml"
+	t.Cleanup(func() { config.AnswerFormat = "" })
+	assert.Equal(t, cli.ExitOK, h.run("history", "resubmit", id))
+	assert.Len(t, h.submitted, 3)
+	assert.Contains(t, h.submitted[2], answer)
+
+	failed := strings.Split(lines[2], "\t")[0]
+	assert.Equal(t, cli.ExitError, h.run("history", "resubmit", failed))
+	assert.Equal(t, cli.ExitError, h.run("history", "show", "missing"))
+	assert.Equal(t, cli.ExitUsage, h.run("history", "show"))
+	assert.Equal(t, cli.ExitUsage, h.run("history", "rewrite"))
+}
+
+func TestBatch(t *testing.T) {
+	h := newHarness(t)
+	h.app.Stdin = strings.NewReader("{\"cifrado\":\"khoor\",\"numero_casas\":3}\n{\"cifrado\":\"\"}\n")
+	assert.Equal(t, cli.ExitError, h.run("batch"))
+	assert.Contains(t, h.stdout.String(), `"decifrado":"hello"`)
+	assert.Contains(t, h.stderr.String(), "-:2: cifrado is required")
+	assert.Contains(t, h.stderr.String(), "2 items, 1 failed")
+
+	in := filepath.Join(t.TempDir(), "items.csv")
+	out := filepath.Join(t.TempDir(), "results.jsonl")
+	os.WriteFile(in, []byte("id,cifrado\n1,khoor\n"), 0644)
+	assert.Equal(t, cli.ExitOK, h.run("batch", "-in", in, "-out", out, "-workers", "2"))
+	data, _ := os.ReadFile(out)
+	assert.Contains(t, string(data), `{"linha":2,"id":"1","numero_casas":3,"cifrado":"khoor","decifrado":"hello"`)
+
+	assert.Equal(t, cli.ExitUsage, h.run("batch", "-workers", "0"))
+	assert.Equal(t, cli.ExitUsage, h.run("batch", "-in-format", "xml"))
+}
+
+func TestWatch(t *testing.T) {
+	h := newHarness(t)
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "msg.txt"), []byte("khoor"), 0644)
+
+	assert.Equal(t, cli.ExitOK, h.run("watch", "-once", "-settle", "1ns", dir))
+	assert.Equal(t, filepath.Join(dir, "done", "msg.txt.answer.json")+"\n", h.stdout.String())
+	assert.Equal(t, cli.ExitUsage, h.run("watch"))
+	assert.Equal(t, cli.ExitUsage, h.run("watch", "-once", "-places", "26", dir))
+}
+
+func TestLogging(t *testing.T) {
+	h := newHarness(t)
+	assert.Equal(t, cli.ExitOK, h.run("run", "-answer", h.file, "-log-level", "debug", "-log-format", "json"))
+	assert.Equal(t, "{\"score\":100}\n", h.stdout.String())
+
+	lines := 0
+	for _, line := range strings.Split(h.stderr.String(), "\n") {
+		if strings.HasPrefix(line, "{") {
+			lines++
+			assert.Contains(t, line, `"run_id":"`)
+		}
+	}
+	assert.Greater(t, lines, 4)
+	assert.Contains(t, h.stderr.String(), `"msg":"answer written"`)
+
+	assert.Equal(t, cli.ExitUsage, h.run("verify", "-log-level", "loud"))
+	assert.Equal(t, cli.ExitUsage, h.run("verify", "-log-format", "xml"))
+}
+
+func TestAffine(t *testing.T) {
+	h := newHarness(t)
+	assert.Equal(t, cli.ExitOK, h.run("affine", "-a", "5", "-b", "8", "affine", "cipher"))
+	assert.Equal(t, "ihhwvc swfrcp\n", h.stdout.String())
+
+	assert.Equal(t, cli.ExitOK, h.run("affine", "-decrypt", "-a", "5", "-b", "8", "ihhwvc", "swfrcp"))
+	assert.Equal(t, "affine cipher\n", h.stdout.String())
+
+	assert.Equal(t, cli.ExitOK, h.run("affine", "-crack", "-top", "1", "gwwf", "gw", "ef", "fdw", "ufefocr", "ef", "rccr"))
+	assert.Equal(t, "11\t 4\t", h.stdout.String()[:6])
+
+	assert.Equal(t, cli.ExitUsage, h.run("affine", "-a", "13", "text"))
+	assert.Equal(t, cli.ExitUsage, h.run("affine"))
+}
+
+func TestPipeline(t *testing.T) {
+	h := newHarness(t)
+	assert.Equal(t, cli.ExitOK, h.run("encrypt", "-pipeline", "caesar:3|reverse|base64", "hello", "world"))
+	assert.Equal(t, "Z291cnogcm9vaGs=\n", h.stdout.String())
+
+	assert.Equal(t, cli.ExitOK, h.run("decrypt", "-pipeline", "caesar:3|reverse|base64", "Z291cnogcm9vaGs="))
+	assert.Equal(t, "hello world\n"+crypto.Summary("hello world")+"\n", h.stdout.String())
+
+	assert.Equal(t, cli.ExitOK, h.run("fetch", "-answer", h.file))
+	assert.Equal(t, cli.ExitOK, h.run("decrypt", "-answer", h.file, "-pipeline", "caesar:3"))
+	assert.Equal(t, "the quick brown fox jumps over the lazy dog.\n", h.stdout.String())
+
+	os.WriteFile(h.file, []byte(`{"numero_casas":3,"token":"token","cifrado":"Z291cnogcm9vaGs="}`), 0644)
+	assert.Equal(t, cli.ExitOK, h.run("decrypt", "-answer", h.file, "-pipeline", "caesar:3|reverse|base64"))
+	assert.Equal(t, cli.ExitOK, h.run("verify", "-answer", h.file))
+	data, _ := os.ReadFile(h.file)
+	assert.Contains(t, string(data), `"decifrado":"hello world"`)
+	assert.Contains(t, string(data), `"pipeline":"caesar:3|reverse|base64"`)
+	assert.Equal(t, cli.ExitOK, h.run("submit", "-answer", h.file))
+	assert.Len(t, h.submitted, 1)
+
+	os.WriteFile(h.file, []byte(strings.Replace(string(data), "Z291", "Y291", 1)), 0644)
+	assert.Equal(t, cli.ExitUnverified, h.run("verify", "-answer", h.file))
+
+	assert.Equal(t, cli.ExitUsage, h.run("encrypt", "-pipeline", "rot13", "text"))
+	assert.Equal(t, cli.ExitError, h.run("decrypt", "-pipeline", "base64", "not base64!"))
+}
+
+func TestAlphabet(t *testing.T) {
+	h := newHarness(t)
+	assert.Equal(t, cli.ExitOK, h.run("encrypt", "-alphabet", "printable", "-places", "47", "Hello,", "World!"))
+	assert.Equal(t, "w6==@[ (@C=5P\n", h.stdout.String())
+
+	assert.Equal(t, cli.ExitOK, h.run("decrypt", "-alphabet", "cyrillic", "-places", "3", "тулезх,", "плу"))
+	assert.Equal(t, "привет, мир\n", h.stdout.String()[:len("привет, мир\n")])
+
+	assert.Equal(t, cli.ExitOK, h.run("decrypt", "-alphabet", "printable", "-output", "json", "*|y", "'+}w!", "v(%-$", "z%.", "~+#&)", "%,y(", "*|y", `"u0/`, "x%{"))
+	assert.Contains(t, h.stdout.String(), `"decifrado":"the quick brown fox jumps over the lazy dog"`)
+
+	for _, c := range []struct{ alphabet, places, text string }{
+		{"cyrillic", "5", "в чащах юга жил бы цитрус, да, но фальшивый экземпляр"},
+		{"greek", "9", "η γλωσσα ειναι ωραια και πλουσια"},
+		{"printable", "47", "Meet me at the usual place at ten, bring the documents."},
+	} {
+		assert.Equal(t, cli.ExitOK, h.run("encrypt", "-alphabet", c.alphabet, "-places", c.places, c.text))
+		ciphertext := strings.TrimSuffix(h.stdout.String(), "\n")
+		assert.Equal(t, cli.ExitOK, h.run("decrypt", "-alphabet", c.alphabet, "-output", "json", ciphertext))
+		assert.Contains(t, h.stdout.String(), `"numero_casas":`+c.places+`,`)
+		assert.Contains(t, h.stdout.String(), `"decifrado":"`+c.text+`"`)
+	}
+
+	assert.Equal(t, cli.ExitUsage, h.run("decrypt", "-alphabet", "ACGT", "TCAACGC"))
+	assert.Contains(t, h.stderr.String(), "-places is required")
+
+	assert.Equal(t, cli.ExitOK, h.run("encrypt", "-unknown", "drop", "-places", "1", "a b"))
+	assert.Equal(t, "bc\n", h.stdout.String())
+
+	assert.Equal(t, cli.ExitOK, h.run("fetch", "-answer", h.file))
+	assert.Equal(t, cli.ExitError, h.run("decrypt", "-answer", h.file, "-unknown", "error"))
+	assert.Equal(t, cli.ExitUsage, h.run("decrypt", "-answer", h.file, "-unknown", "drop"))
+
+	os.WriteFile(h.file, []byte(`{"numero_casas":47,"token":"token","cifrado":"w6==@[ (@C=5P"}`), 0644)
+	assert.Equal(t, cli.ExitOK, h.run("decrypt", "-answer", h.file, "-alphabet", "printable"))
+	assert.Equal(t, "hello, world!\n", h.stdout.String())
+	assert.Equal(t, cli.ExitOK, h.run("verify", "-answer", h.file))
+
+	assert.Equal(t, cli.ExitOK, h.run("decrypt", "-answer", h.file))
+	assert.Equal(t, cli.ExitUnverified, h.run("verify", "-answer", h.file))
+
+	assert.Equal(t, cli.ExitUsage, h.run("encrypt", "-alphabet", "aa", "text"))
+	assert.Equal(t, cli.ExitUsage, h.run("encrypt", "-unknown", "skip", "text"))
+}
+
+func TestTranspose(t *testing.T) {
+	h := newHarness(t)
+	assert.Equal(t, cli.ExitOK, h.run("transpose", "-rails", "3", "wearediscoveredfleeatonce"))
+	assert.Equal(t, "wecrlteerdsoeefeaocaivden\n", h.stdout.String())
+
+	assert.Equal(t, cli.ExitOK, h.run("transpose", "-key", "zebras", "-places", "3", "wearediscoveredfleeatonce"))
+	ciphertext := strings.TrimSpace(h.stdout.String())
+	assert.Equal(t, cli.ExitOK, h.run("transpose", "-decrypt", "-key", "zebras", "-places", "3", ciphertext))
+	assert.Equal(t, "wearediscoveredfleeatonce\n", h.stdout.String())
+
+	assert.Equal(t, cli.ExitOK, h.run("transpose", "-rails", "4", "meet me by the old bridge at noon"))
+	ciphertext = strings.TrimSuffix(h.stdout.String(), "\n")
+	assert.Equal(t, cli.ExitOK, h.run("transpose", "-crack", "-top", "1", ciphertext))
+	assert.Equal(t, "railfence\t4\t 0\t", h.stdout.String()[:15])
+	assert.Contains(t, h.stdout.String(), "meet me by the old bridge at noon")
+
+	assert.Equal(t, cli.ExitUsage, h.run("transpose", "text"))
+	assert.Equal(t, cli.ExitUsage, h.run("transpose", "-rails", "2", "-key", "k", "text"))
+	assert.Equal(t, cli.ExitUsage, h.run("transpose", "-rails", "1", "text"))
+	assert.Equal(t, cli.ExitUsage, h.run("transpose", "-crack", "-max-columns", "10", "text"))
+}
+
+func TestSolve(t *testing.T) {
+	h := newHarness(t)
+	h.app.Stdin = strings.NewReader("oz vql q wkouiz egsr rqn of qhkos qfr zit esgeal vtkt\n")
+	assert.Equal(t, cli.ExitOK, h.run("solve", "-in", "-", "-seed", "1", "-restarts", "2", "-steps", "100", "-crib", "0:it was a bright cold day"))
+	assert.Contains(t, h.stdout.String(), "\nit was a bright cold day ")
+	assert.Contains(t, h.stdout.String(), "key\t")
+
+	assert.Equal(t, cli.ExitUsage, h.run("solve"))
+	assert.Equal(t, cli.ExitUsage, h.run("solve", "-crib", "bad", "text"))
+	assert.Equal(t, cli.ExitUsage, h.run("solve", "-crib", "5:long crib", "abc"))
+}
+
+func TestAnalyze(t *testing.T) {
+	h := newHarness(t)
+	assert.Equal(t, cli.ExitOK, h.run("fetch", "-answer", h.file))
+	assert.Equal(t, cli.ExitOK, h.run("analyze", "-answer", h.file))
+	assert.Contains(t, h.stdout.String(), "letters\t35\nioc\t0.0218\n")
+	assert.Contains(t, h.stdout.String(), "letter\tcount")
+
+	assert.Equal(t, cli.ExitOK, h.run("analyze", "-output", "json", "abcabc"))
+	assert.Contains(t, h.stdout.String(), `"family":"unknown"`)
+	assert.Contains(t, h.stdout.String(), `"ngram":"abc"`)
+
+	assert.Equal(t, cli.ExitUsage, h.run("analyze", "-output", "xml", "abc"))
+}
+
+func TestTrain(t *testing.T) {
+	h := newHarness(t)
+	corpus := filepath.Join(t.TempDir(), "corpus.txt")
+	assert.NoError(t, os.WriteFile(corpus, []byte("o tempo esta bonito hoje e nos vamos para a feira"), 0o644))
+	model := filepath.Join(t.TempDir(), "pt.json")
+
+	assert.Equal(t, cli.ExitOK, h.run("train", "-out", model, corpus))
+	assert.Contains(t, h.stdout.String(), "model pt saved")
+
+	assert.Equal(t, cli.ExitOK, h.run("crack", "-lang", model, "-top", "1", "z epxaz", "pdel"))
+	assert.Equal(t, "11\t", h.stdout.String()[:3])
+
+	assert.Equal(t, cli.ExitOK, h.run("crack", "-lang", "en", "-top", "1", "wkh", "txlfn", "eurzq", "ira"))
+	assert.Equal(t, " 3\t", h.stdout.String()[:3])
+
+	assert.Equal(t, cli.ExitError, h.run("crack", "-lang", "klingon", "text"))
+	assert.Equal(t, cli.ExitUsage, h.run("train"))
+}
+
+func TestMetricsFile(t *testing.T) {
+	h := newHarness(t)
+	file := filepath.Join(t.TempDir(), "metrics.prom")
+	assert.Equal(t, cli.ExitOK, h.run("decrypt", "-metrics-file", file, "-places", "3", "khoor"))
+	data, _ := os.ReadFile(file)
+	assert.Contains(t, string(data), "caesar_decrypted_bytes_total")
+
+	assert.Equal(t, cli.ExitOK, h.run("encrypt", "-metrics-addr", "127.0.0.1:0", "hello"))
+	assert.Equal(t, cli.ExitError, h.run("encrypt", "-metrics-addr", "bad:addr:0", "hello"))
+}
+
+func TestTracing(t *testing.T) {
+	h := newHarness(t)
+	assert.Equal(t, cli.ExitOK, h.run("fetch", "-answer", h.file))
+	assert.Equal(t, cli.ExitOK, h.run("decrypt", "-answer", h.file, "-trace", "stdout"))
+	assert.Equal(t, "the quick brown fox jumps over the lazy dog.\n", h.stdout.String())
+	assert.Contains(t, h.stderr.String(), `"Name":"caesar decrypt"`)
+	assert.Contains(t, h.stderr.String(), `"Name":"crypto.Decrypt"`)
+	assert.Contains(t, h.stderr.String(), `"Name":"writer.WriteAnswer"`)
+
+	assert.Equal(t, cli.ExitOK, h.run("decrypt", "-output", "json", "-trace", "stdout", "khoor"))
+	assert.JSONEq(t, `{"numero_casas":3,"cifrado":"khoor","decifrado":"hello","resumo_criptografico":"`+crypto.Summary("hello")+`"}`, h.stdout.String())
+
+	assert.Equal(t, cli.ExitUsage, h.run("decrypt", "-trace", "zipkin"))
+}
@@ -1,66 +0,0 @@
-As the provided source code is a configuration file and does not contain any functions or methods to test, it's not possible to write unit tests for it. However, if there were functions that use these variables, we could write tests for those functions based on the provided test scenarios.
-
-Here is an example of how you might write tests for a hypothetical function that uses these variables:
-
-```go
-package config
-
-import (
-	"os"
-	"testing"
-)
-
-func TestFunction(t *testing.T) {
-	// Save current environment variables and restore them after the test
-	oldBaseUrl := os.Getenv("BASE_URL")
-	oldToken := os.Getenv("TOKEN_CODENATION")
-	defer func() {
-		os.Setenv("BASE_URL", oldBaseUrl)
-		os.Setenv("TOKEN_CODENATION", oldToken)
-	}()
-
-	// Test case: Environment variables are set correctly
-	os.Setenv("BASE_URL", "http://localhost:8000/")
-	os.Setenv("TOKEN_CODENATION", "123456")
-	// Call the function and check the results
-	// ...
-
-	// Test case: Environment variables are not set
-	os.Unsetenv("BASE_URL")
-	os.Unsetenv("TOKEN_CODENATION")
-	// Call the function and check the results
-	// ...
-
-	// Test case: Environment variables are set to unexpected values
-	os.Setenv("BASE_URL", "not a url")
-	os.Setenv("TOKEN_CODENATION", "")
-	// Call the function and check the results
-	// ...
-
-	// Test case: Environment variables contain special characters
-	os.Setenv("BASE_URL", "http://example.com/?query=param")
-	os.Setenv("TOKEN_CODENATION", "abc$%^&*()")
-	// Call the function and check the results
-	// ...
-
-	// Test case: Environment variables contain extremely long values
-	os.Setenv("BASE_URL", "http://very-long-url.com/")
-	os.Setenv("TOKEN_CODENATION", "very-long-token")
-	// Call the function and check the results
-	// ...
-
-	// Test case: Environment variables contain non-ASCII characters
-	os.Setenv("BASE_URL", "http://example.com/ümlaut")
-	os.Setenv("TOKEN_CODENATION", "tökën")
-	// Call the function and check the results
-	// ...
-
-	// Test case: Environment variables contain leading or trailing whitespace
-	os.Setenv("BASE_URL", " http://example.com/ ")
-	os.Setenv("TOKEN_CODENATION", " 123456 ")
-	// Call the function and check the results
-	// ...
-}
-```
-
-Please note that this is a hypothetical test for a hypothetical function. The actual tests would depend on the specific function you're testing.
\ No newline at end of file
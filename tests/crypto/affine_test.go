@@ -0,0 +1,65 @@
+package crypto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wesleyholiveira/caesar-challenge/crypto"
+)
+
+func TestAffine(t *testing.T) {
+	key := crypto.AffineKey{A: 5, B: 8}
+	ciphertext, err := crypto.EncryptAffine("Affine Cipher!", key)
+	assert.NoError(t, err)
+	assert.Equal(t, "ihhwvc swfrcp!", ciphertext)
+
+	plaintext, err := crypto.DecryptAffine(ciphertext, key)
+	assert.NoError(t, err)
+	assert.Equal(t, "affine cipher!", plaintext)
+
+	caesar, _ := crypto.EncryptAffine("hello", crypto.AffineKey{A: 1, B: 3})
+	assert.Equal(t, crypto.EncryptText("hello", 3), caesar)
+
+	negative, _ := crypto.DecryptAffine(ciphertext, crypto.AffineKey{A: 5 - 26, B: 8 - 26})
+	assert.Equal(t, plaintext, negative)
+
+	_, err = crypto.EncryptAffine("hello", crypto.AffineKey{A: 13, B: 1})
+	assert.Error(t, err)
+	_, err = crypto.DecryptAffine("hello", crypto.AffineKey{A: 2, B: 1})
+	assert.Error(t, err)
+}
+
+func TestAffineAlphabet(t *testing.T) {
+	key := crypto.AffineKey{A: 7, B: 3, Alphabet: "abcdefghijklmnopqrstuvwxyz0123456789"}
+	ciphertext, err := crypto.EncryptAffine("Room 101", key)
+	assert.NoError(t, err)
+	assert.NotContains(t, ciphertext, "101")
+
+	plaintext, _ := crypto.DecryptAffine(ciphertext, key)
+	assert.Equal(t, "room 101", plaintext)
+
+	key = crypto.AffineKey{A: 5, B: 1, Alphabet: "ABCabc"}
+	ciphertext, _ = crypto.EncryptAffine("Abc-x", key)
+	assert.Equal(t, "BaC-x", ciphertext)
+	plaintext, _ = crypto.DecryptAffine(ciphertext, key)
+	assert.Equal(t, "Abc-x", plaintext)
+
+	_, err = crypto.EncryptAffine("hello", crypto.AffineKey{A: 1, Alphabet: "abca"})
+	assert.ErrorIs(t, err, crypto.ErrInvalidAlphabet)
+	_, err = crypto.EncryptAffine("hello", crypto.AffineKey{A: 1, Alphabet: "a"})
+	assert.ErrorIs(t, err, crypto.ErrInvalidAlphabet)
+	_, err = crypto.EncryptAffine("hello", crypto.AffineKey{A: 6, Alphabet: "abcdefghijklmnopqrstuvwxyz0123456789"})
+	assert.Error(t, err)
+}
+
+func TestCrackAffine(t *testing.T) {
+	ciphertext, _ := crypto.EncryptAffine("meet me at the station at noon", crypto.AffineKey{A: 11, B: 4})
+	candidates, err := crypto.CrackAffine(ciphertext, "")
+	assert.NoError(t, err)
+	assert.Len(t, candidates, 12*26)
+	assert.Equal(t, crypto.AffineKey{A: 11, B: 4}, candidates[0].Key)
+	assert.Equal(t, "meet me at the station at noon", candidates[0].Text)
+
+	_, err = crypto.CrackAffine(ciphertext, "aa")
+	assert.ErrorIs(t, err, crypto.ErrInvalidAlphabet)
+}
@@ -0,0 +1,100 @@
+package crypto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wesleyholiveira/caesar-challenge/crypto"
+)
+
+func TestAlphabet(t *testing.T) {
+	out, err := crypto.Printable.Encrypt("Hello, World!", 47, crypto.KeepUnknown)
+	assert.NoError(t, err)
+	assert.Equal(t, "w6==@[ (@C=5P", out)
+	out, _ = crypto.Printable.Encrypt(out, 47, crypto.KeepUnknown)
+	assert.Equal(t, "Hello, World!", out)
+
+	out, _ = crypto.Alphanumeric.Encrypt("Agent 007", 5, crypto.KeepUnknown)
+	assert.Equal(t, "fljsy 55c", out)
+	out, _ = crypto.Alphanumeric.Decrypt(out, 5, crypto.KeepUnknown)
+	assert.Equal(t, "agent 007", out)
+
+	out, _ = crypto.Cyrillic.Encrypt("Привет, мир", 3, crypto.KeepUnknown)
+	assert.Equal(t, "тулезх, плу", out)
+	out, _ = crypto.Cyrillic.Decrypt(out, 3, crypto.KeepUnknown)
+	assert.Equal(t, "привет, мир", out)
+
+	out, _ = crypto.Greek.Encrypt("αλφα ωμεγα", 1, crypto.KeepUnknown)
+	assert.Equal(t, "βμχβ ανζδβ", out)
+
+	out, _ = crypto.Latin.Decrypt("Khoor, Zruog!", -23, crypto.KeepUnknown)
+	assert.Equal(t, crypto.DecryptText("Khoor, Zruog!", 3), out)
+}
+
+func TestAlphabetUnknown(t *testing.T) {
+	out, err := crypto.Latin.Encrypt("a b-c", 1, crypto.DropUnknown)
+	assert.NoError(t, err)
+	assert.Equal(t, "bcd", out)
+
+	_, err = crypto.Latin.Encrypt("a b", 1, crypto.RejectUnknown)
+	assert.ErrorIs(t, err, crypto.ErrOutOfAlphabet)
+	assert.ErrorContains(t, err, "' ' at byte 1")
+
+	unknown, err := crypto.ParseUnknown("DROP")
+	assert.NoError(t, err)
+	assert.Equal(t, crypto.DropUnknown, unknown)
+	_, err = crypto.ParseUnknown("skip")
+	assert.Error(t, err)
+}
+
+func TestLookupAlphabet(t *testing.T) {
+	a, err := crypto.LookupAlphabet("")
+	assert.NoError(t, err)
+	assert.Equal(t, crypto.Latin, a)
+
+	a, _ = crypto.LookupAlphabet("Greek")
+	assert.Equal(t, 24, a.Size())
+
+	a, err = crypto.LookupAlphabet("ACGT")
+	assert.NoError(t, err)
+	out, _ := a.Encrypt("GATTACA acgt", 1, crypto.KeepUnknown)
+	assert.Equal(t, "TCAACGC acgt", out)
+
+	_, err = crypto.LookupAlphabet("abca")
+	assert.ErrorIs(t, err, crypto.ErrInvalidAlphabet)
+}
+
+func TestCrackAlphabet(t *testing.T) {
+	ciphertext, _ := crypto.Printable.Encrypt("The quick brown fox jumps over the lazy dog", 20, crypto.KeepUnknown)
+	candidates := crypto.CrackAlphabet(ciphertext, crypto.Printable, crypto.Score)
+	assert.Len(t, candidates, 94)
+	assert.Equal(t, 20, candidates[0].Places)
+
+	for _, c := range []struct {
+		alphabet *crypto.Alphabet
+		text     string
+		places   int
+	}{
+		{crypto.Printable, "Meet me at the usual place at ten, bring the documents.", 47},
+		{crypto.Printable, "It was a bright cold day in April.", 32},
+		{crypto.Cyrillic, "в чащах юга жил бы цитрус, да, но фальшивый экземпляр", 3},
+		{crypto.Cyrillic, "москва столица россии", 13},
+		{crypto.Greek, "η γλωσσα ειναι ωραια και πλουσια", 7},
+		{crypto.Greek, "το ελληνικο αλφαβητο εχει εικοσι τεσσερα γραμματα.", 20},
+	} {
+		ciphertext, _ := c.alphabet.Encrypt(c.text, c.places, crypto.KeepUnknown)
+		best := crypto.CrackAlphabet(ciphertext, c.alphabet, c.alphabet.Scorer())[0]
+		assert.Equal(t, c.places, best.Places, c.text)
+		assert.Equal(t, c.text, best.Text)
+	}
+
+	a, _ := crypto.LookupAlphabet("ACGT")
+	assert.Nil(t, a.Scorer())
+
+	p, err := crypto.ParsePipeline("caesar:47,printable|base64")
+	assert.NoError(t, err)
+	assert.Equal(t, "caesar:47,printable|base64", p.String())
+	out, _ := p.Encrypt("Hello")
+	out, _ = p.Decrypt(out)
+	assert.Equal(t, "Hello", out)
+}
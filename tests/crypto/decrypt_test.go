@@ -0,0 +1,33 @@
+package crypto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wesleyholiveira/caesar-challenge/crypto"
+)
+
+func TestDecryptText(t *testing.T) {
+	assert.Equal(t, "abc", crypto.DecryptText("bcd", 1))
+	assert.Equal(t, "abc", crypto.DecryptText("BCD", 1))
+	assert.Equal(t, "xyz", crypto.DecryptText("abc", 3))
+	assert.Equal(t, "bcd", crypto.DecryptText("abc", -1))
+	assert.Equal(t, "bcd", crypto.DecryptText("abc", 25))
+	assert.Equal(t, "abc def. 123!", crypto.DecryptText("bcd efg. 123!", 1))
+	assert.Equal(t, "abc£€¥", crypto.DecryptText("bcd£€¥", 1))
+}
+
+func TestSummary(t *testing.T) {
+	assert.Equal(t, "a9993e364706816aba3e25717850c26c9cd0d89d", crypto.Summary("abc"))
+	assert.Equal(t, "da39a3ee5e6b4b0d3255bfef95601890afd80709", crypto.Summary(""))
+}
+
+func TestCrack(t *testing.T) {
+	ciphertext := crypto.EncryptText("the quick brown fox jumps over the lazy dog", 7)
+	candidates := crypto.Crack(ciphertext)
+
+	assert.Len(t, candidates, 26)
+	assert.Equal(t, 7, candidates[0].Places)
+	assert.Equal(t, "the quick brown fox jumps over the lazy dog", candidates[0].Text)
+	assert.True(t, candidates[0].Score > candidates[1].Score)
+}
@@ -0,0 +1,83 @@
+package crypto
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wesleyholiveira/caesar-challenge/crypto"
+	"github.com/wesleyholiveira/caesar-challenge/model"
+	"github.com/wesleyholiveira/caesar-challenge/writer"
+)
+
+func TestPipeline(t *testing.T) {
+	p, err := crypto.ParsePipeline("caesar:3 | reverse | base64")
+	assert.NoError(t, err)
+	assert.Equal(t, "caesar:3|reverse|base64", p.String())
+
+	ciphertext, err := p.Encrypt("hello world")
+	assert.NoError(t, err)
+	assert.Equal(t, "Z291cnogcm9vaGs=", ciphertext)
+
+	plaintext, err := p.Decrypt(ciphertext)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello world", plaintext)
+
+	_, err = p.Decrypt("not base64!")
+	assert.ErrorContains(t, err, "base64")
+}
+
+func TestPipelineStages(t *testing.T) {
+	specs := []string{
+		"caesar:-5", "affine:5,8", "substitution:qwertyuiopasdfghjklzxcvbnm", "railfence:3", "columnar:zebras",
+		"reverse", "base64", "hex", "url", "url|hex|caesar:1|railfence:4|base64",
+	}
+
+	for _, spec := range specs {
+		p, err := crypto.ParsePipeline(spec)
+		assert.NoError(t, err, spec)
+
+		ciphertext, err := p.Encrypt("we are discovered, flee at once!")
+		assert.NoError(t, err, spec)
+		plaintext, err := p.Decrypt(ciphertext)
+		assert.NoError(t, err, spec)
+		assert.Equal(t, "we are discovered, flee at once!", plaintext, spec)
+	}
+
+	hex, _ := crypto.ParsePipeline("hex")
+	out, _ := hex.Encrypt("hi")
+	assert.Equal(t, "6869", out)
+
+	url, _ := crypto.ParsePipeline("url")
+	out, _ = url.Encrypt("a b&c")
+	assert.Equal(t, "a+b%26c", out)
+
+	for _, spec := range []string{"", "rot13", "caesar", "caesar:x", "affine:2,1", "affine:3", "railfence:1", "columnar", "substitution:abc", "reverse:1"} {
+		_, err := crypto.ParsePipeline(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestDecryptPipeline(t *testing.T) {
+	p, _ := crypto.ParsePipeline("caesar:3|reverse|base64")
+	w := writer.New()
+	w.File = filepath.Join(t.TempDir(), "answer.json")
+	w.Response = &model.ChallengeResponse{CryptedText: "Z291cnogcm9vaGs="}
+
+	assert.NoError(t, crypto.DecryptPipeline(w, p))
+	r := w.Response.(*model.ChallengeResponse)
+	assert.Equal(t, "hello world", r.DecryptedText)
+	assert.Equal(t, "caesar:3|reverse|base64", r.Pipeline)
+	decrypted, err := crypto.DecryptAnswer(r)
+	assert.NoError(t, err)
+	assert.Equal(t, r.DecryptedText, decrypted)
+
+	assert.Equal(t, crypto.Summary("hello world"), r.SummaryCrypto)
+	assert.FileExists(t, w.File)
+
+	// the answer is lowered like a Caesar decryption
+	p, _ = crypto.ParsePipeline("caesar:47,printable")
+	r.CryptedText = "w6==@[ (@C=5P"
+	assert.NoError(t, crypto.DecryptPipeline(w, p))
+	assert.Equal(t, "hello, world!", r.DecryptedText)
+}
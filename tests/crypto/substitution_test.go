@@ -0,0 +1,83 @@
+package crypto
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wesleyholiveira/caesar-challenge/crypto"
+)
+
+const substitutionKey = "qwertyuiopasdfghjklzxcvbnm"
+
+const substitutionPlaintext = "it was a bright cold day in april and the clocks were striking thirteen while the wind swept " +
+	"little eddies of dust and torn paper along the street and into the open doors of the houses where " +
+	"the families were sitting down to their evening meals after a long day of work in the fields"
+
+func TestSubstitution(t *testing.T) {
+	ciphertext, err := crypto.EncryptSubstitution("Hello, World!", substitutionKey)
+	assert.NoError(t, err)
+	assert.Equal(t, "itssg, vgksr!", ciphertext)
+
+	plaintext, err := crypto.DecryptSubstitution(ciphertext, substitutionKey)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello, world!", plaintext)
+
+	caesar, _ := crypto.EncryptSubstitution("hello", crypto.SubstitutionKey(3))
+	assert.Equal(t, crypto.EncryptText("hello", 3), caesar)
+
+	_, err = crypto.EncryptSubstitution("hello", "abc")
+	assert.ErrorIs(t, err, crypto.ErrInvalidKey)
+	_, err = crypto.DecryptSubstitution("hello", "aacdefghijklmnopqrstuvwxyz")
+	assert.ErrorIs(t, err, crypto.ErrInvalidKey)
+}
+
+func TestSolveSubstitution(t *testing.T) {
+	ciphertext, _ := crypto.EncryptSubstitution(substitutionPlaintext, substitutionKey)
+
+	solution, err := crypto.SolveSubstitution(ciphertext, crypto.SubstitutionOptions{
+		Rand:        rand.New(rand.NewSource(1)),
+		Temperature: 20,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, substitutionPlaintext, solution.Text)
+
+	decrypted, _ := crypto.DecryptSubstitution(ciphertext, solution.Key)
+	assert.Equal(t, solution.Text, decrypted)
+
+	solution, err = crypto.SolveSubstitution(ciphertext, crypto.SubstitutionOptions{
+		Rand:  rand.New(rand.NewSource(2)),
+		Cribs: []crypto.Crib{{Offset: 0, Text: "it was"}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, substitutionPlaintext, solution.Text)
+	assert.Equal(t, "itwas", solution.Text[:2]+solution.Text[3:6])
+}
+
+func TestSolveSubstitutionCribs(t *testing.T) {
+	_, err := crypto.SolveSubstitution("abc", crypto.SubstitutionOptions{Cribs: []crypto.Crib{{Offset: 2, Text: "xy"}}})
+	assert.Error(t, err)
+
+	_, err = crypto.SolveSubstitution("aa", crypto.SubstitutionOptions{Cribs: []crypto.Crib{{Text: "xy"}}})
+	assert.Error(t, err)
+
+	_, err = crypto.SolveSubstitution("ab", crypto.SubstitutionOptions{Cribs: []crypto.Crib{{Text: "xx"}}})
+	assert.Error(t, err)
+
+	solution, err := crypto.SolveSubstitution("abc", crypto.SubstitutionOptions{Cribs: []crypto.Crib{{Text: "the"}}})
+	assert.NoError(t, err)
+	assert.Equal(t, "the", solution.Text)
+}
+
+func TestSolveSubstitutionUnscored(t *testing.T) {
+	for _, score := range []float64{math.Inf(-1), math.NaN()} {
+		score := score
+		_, err := crypto.SolveSubstitution("khoor", crypto.SubstitutionOptions{
+			Scorer:   func(string) float64 { return score },
+			Restarts: 2,
+			Steps:    10,
+		})
+		assert.ErrorIs(t, err, crypto.ErrUnscored)
+	}
+}
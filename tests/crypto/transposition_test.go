@@ -0,0 +1,77 @@
+package crypto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wesleyholiveira/caesar-challenge/crypto"
+)
+
+const transpositionPlaintext = "we are discovered flee at once and meet me by the old bridge at noon"
+
+func TestRailFence(t *testing.T) {
+	ciphertext, err := crypto.EncryptRailFence("wearediscoveredfleeatonce", 3)
+	assert.NoError(t, err)
+	assert.Equal(t, "wecrlteerdsoeefeaocaivden", ciphertext)
+
+	plaintext, err := crypto.DecryptRailFence(ciphertext, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, "wearediscoveredfleeatonce", plaintext)
+
+	for rails := 2; rails < 12; rails++ {
+		ciphertext, _ := crypto.EncryptRailFence(transpositionPlaintext, rails)
+		plaintext, _ := crypto.DecryptRailFence(ciphertext, rails)
+		assert.Equal(t, transpositionPlaintext, plaintext, "%d rails", rails)
+	}
+
+	_, err = crypto.EncryptRailFence("text", 1)
+	assert.ErrorIs(t, err, crypto.ErrInvalidRails)
+	_, err = crypto.DecryptRailFence("text", 0)
+	assert.ErrorIs(t, err, crypto.ErrInvalidRails)
+}
+
+func TestColumnar(t *testing.T) {
+	ciphertext, err := crypto.EncryptColumnar("wearediscoveredfleeatonce", "zebras")
+	assert.NoError(t, err)
+	assert.Equal(t, "evlnacdtesearofodeecwiree", ciphertext)
+
+	plaintext, err := crypto.DecryptColumnar(ciphertext, "zebras")
+	assert.NoError(t, err)
+	assert.Equal(t, "wearediscoveredfleeatonce", plaintext)
+
+	for _, key := range []string{"a", "ab", "ba", "key", "letter", "longerkeyword"} {
+		ciphertext, _ := crypto.EncryptColumnar(transpositionPlaintext, key)
+		plaintext, _ := crypto.DecryptColumnar(ciphertext, key)
+		assert.Equal(t, transpositionPlaintext, plaintext, key)
+	}
+
+	_, err = crypto.EncryptColumnar("text", "")
+	assert.ErrorIs(t, err, crypto.ErrEmptyColumnar)
+}
+
+func TestCrackTransposition(t *testing.T) {
+	ciphertext, _ := crypto.EncryptRailFence(transpositionPlaintext, 4)
+	candidates := crypto.CrackRailFence(ciphertext, crypto.TranspositionOptions{})
+	assert.Len(t, candidates, crypto.DefaultMaxRails-1)
+	assert.Equal(t, 4, candidates[0].Rails)
+	assert.Equal(t, transpositionPlaintext, candidates[0].Text)
+
+	ciphertext, _ = crypto.EncryptColumnar(transpositionPlaintext, "cipher")
+	candidates = crypto.CrackColumnar(ciphertext, crypto.TranspositionOptions{MaxColumns: 6})
+	assert.Equal(t, crypto.Columnar, candidates[0].Cipher)
+	assert.Equal(t, transpositionPlaintext, candidates[0].Text)
+	plaintext, _ := crypto.DecryptColumnar(ciphertext, candidates[0].Key)
+	assert.Equal(t, transpositionPlaintext, plaintext)
+
+	assert.Len(t, candidates, crypto.DefaultColumnarTop)
+	top := crypto.CrackColumnar(ciphertext, crypto.TranspositionOptions{MaxColumns: 6, Top: 3})
+	assert.Equal(t, candidates[:3], top)
+
+	// the shift and the transposition give the same ciphertext in either order
+	shiftedFirst, _ := crypto.EncryptColumnar(crypto.EncryptText(transpositionPlaintext, 7), "cipher")
+	assert.Equal(t, crypto.EncryptText(ciphertext, 7), shiftedFirst)
+
+	candidates = crypto.CrackTransposition(shiftedFirst, crypto.TranspositionOptions{MaxColumns: 6, Caesar: true})
+	assert.Equal(t, 7, candidates[0].Places)
+	assert.Equal(t, transpositionPlaintext, candidates[0].Text)
+}
@@ -0,0 +1,96 @@
+package format
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wesleyholiveira/caesar-challenge/format"
+	"github.com/wesleyholiveira/caesar-challenge/model"
+)
+
+var answer = model.ChallengeResponse{
+	Places:        3,
+	Token:         "token",
+	CryptedText:   "d oljhlud udsrvd, pduurp",
+	DecryptedText: "a ligeira raposa, marrom",
+	SummaryCrypto: "summary",
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, name := range format.Names() {
+		t.Run(name, func(t *testing.T) {
+			f, err := format.Lookup(name)
+			assert.NoError(t, err)
+
+			data, err := f.Encode(&answer)
+			assert.NoError(t, err)
+
+			decoded := model.ChallengeResponse{}
+			assert.NoError(t, f.Decode(data, &decoded))
+			assert.Equal(t, answer, decoded)
+		})
+	}
+}
+
+func TestEncode(t *testing.T) {
+	data, err := format.JSON.Encode(&answer)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), `{"numero_casas":3,`))
+
+	data, err = format.PrettyJSON.Encode(&answer)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), "{\n  \"numero_casas\": 3,"))
+
+	data, err = format.YAML.Encode(&answer)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "numero_casas: 3\n")
+
+	data, err = format.TOML.Encode(&answer)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "numero_casas = 3\n")
+
+	data, err = format.CSV.Encode(&answer)
+	assert.NoError(t, err)
+	assert.Equal(t, "numero_casas,token,cifrado,decifrado,resumo_criptografico,pipeline\n"+
+		"3,token,\"d oljhlud udsrvd, pduurp\",\"a ligeira raposa, marrom\",summary,\n", string(data))
+
+	_, err = format.CSV.Encode("not a struct")
+	assert.Error(t, err)
+}
+
+func TestCSVRows(t *testing.T) {
+	rows := []model.ChallengeResponse{answer, {Places: 1, CryptedText: "bcd"}}
+
+	data, err := format.CSV.Encode(rows)
+	assert.NoError(t, err)
+
+	decoded := []model.ChallengeResponse{}
+	assert.NoError(t, format.CSV.Decode(data, &decoded))
+	assert.Equal(t, rows, decoded)
+
+	assert.Error(t, format.CSV.Decode([]byte("numero_casas\nabc\n"), &model.ChallengeResponse{}))
+	assert.Error(t, format.CSV.Decode([]byte("numero_casas\n"), &model.ChallengeResponse{}))
+}
+
+func TestResolve(t *testing.T) {
+	assert.Equal(t, format.JSON, format.ForFile("answer.json"))
+	assert.Equal(t, format.YAML, format.ForFile("answer.yml"))
+	assert.Equal(t, format.YAML, format.ForFile("ANSWER.YAML"))
+	assert.Equal(t, format.TOML, format.ForFile("answer.toml"))
+	assert.Equal(t, format.CSV, format.ForFile("answer.csv"))
+	assert.Equal(t, format.JSON, format.ForFile("answer"))
+	assert.Equal(t, format.JSON, format.ForFile("answer.txt"))
+	assert.Equal(t, format.YAML, format.ForFile("answer.yaml.gz"))
+
+	f, err := format.Resolve("json-pretty", "answer.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, format.PrettyJSON, f)
+
+	f, err = format.Resolve("", "answer.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, format.YAML, f)
+
+	_, err = format.Resolve("xml", "answer.json")
+	assert.Error(t, err)
+}
@@ -0,0 +1,60 @@
+package history
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wesleyholiveira/caesar-challenge/history"
+	"github.com/wesleyholiveira/caesar-challenge/model"
+)
+
+func TestStore(t *testing.T) {
+	s := history.New(filepath.Join(t.TempDir(), "history.jsonl"))
+
+	runs, err := s.Runs()
+	assert.NoError(t, err)
+	assert.Empty(t, runs)
+
+	answer := &model.ChallengeResponse{Places: 1, CryptedText: "bcd"}
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	assert.NoError(t, s.Append(history.Event{RunID: "run-b", Time: start.Add(time.Minute), Step: "fetch", Answer: answer}))
+	assert.NoError(t, s.Append(history.Event{RunID: "run-a", Time: start, Step: "fetch", Error: "offline"}))
+
+	answer.DecryptedText = "abc"
+	assert.NoError(t, s.Append(history.Event{RunID: "run-b", Time: start.Add(2 * time.Minute), Step: "submit", Answer: answer, Response: "ok"}))
+
+	runs, err = s.Runs()
+	assert.NoError(t, err)
+	assert.Len(t, runs, 2)
+	assert.Equal(t, "run-a", runs[0].ID)
+	assert.Equal(t, "offline", runs[0].Error)
+	assert.Equal(t, "run-b", runs[1].ID)
+	assert.Equal(t, "submit", runs[1].LastStep())
+	assert.Equal(t, "abc", runs[1].Answer.DecryptedText)
+	assert.Equal(t, "", runs[1].Events[0].Answer.DecryptedText)
+	assert.Equal(t, "ok", runs[1].Response)
+
+	r, err := s.Run("run-b")
+	assert.NoError(t, err)
+	assert.Len(t, r.Events, 2)
+
+	_, err = s.Run("run-")
+	assert.Error(t, err)
+
+	_, err = s.Run("run-c")
+	assert.True(t, errors.Is(err, history.ErrNotFound))
+
+	os.WriteFile(s.Path, []byte("not json\n"), 0600)
+	_, err = s.Runs()
+	assert.Error(t, err)
+}
+
+func TestNewRunID(t *testing.T) {
+	a, b := history.NewRunID(), history.NewRunID()
+	assert.NotEqual(t, a, b)
+	assert.Len(t, a, len("20060102T150405-")+8)
+}
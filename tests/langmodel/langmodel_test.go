@@ -0,0 +1,128 @@
+package langmodel
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wesleyholiveira/caesar-challenge/crypto"
+	"github.com/wesleyholiveira/caesar-challenge/langmodel"
+)
+
+const (
+	english    = "the weather is nice today and we are going to the market"
+	portuguese = "o tempo esta bonito hoje e nos vamos para a feira"
+)
+
+func TestLetters(t *testing.T) {
+	assert.Equal(t, "acaoehello", langmodel.Letters("Ação é: Hello, 123!"))
+}
+
+func TestBuiltin(t *testing.T) {
+	en, err := langmodel.Lookup("english")
+	assert.NoError(t, err)
+	pt, err := langmodel.Lookup(langmodel.Portuguese)
+	assert.NoError(t, err)
+
+	for _, n := range langmodel.Orders {
+		assert.Greater(t, en.LogProb(english, n), pt.LogProb(english, n), "order %d", n)
+		assert.Greater(t, pt.LogProb(portuguese, n), en.LogProb(portuguese, n), "order %d", n)
+	}
+
+	assert.Greater(t, en.Quadgram("the dog"), en.Quadgram("qzx jvk"))
+	assert.Equal(t, en.Quadgram(english), en.Score(english))
+	assert.Equal(t, en.Bigram("ab"), en.Score("ab"))
+	assert.Equal(t, en.Unigram("a!"), en.Score("a!"))
+
+	_, err = langmodel.Lookup("klingon")
+	assert.Error(t, err)
+}
+
+func TestBuiltinEnglishCoverage(t *testing.T) {
+	en, _ := langmodel.Builtin(langmodel.English)
+	floor := en.Quadgram("qzxj")
+
+	words := "because through without between another against nothing something however whether " +
+		"question people government knowledge children always every might thought should " +
+		"country himself believe instance number second figure several morning picture"
+	for _, word := range strings.Fields(words) {
+		for i := 0; i+4 <= len(word); i++ {
+			assert.Greater(t, en.Quadgram(word[i:i+4]), floor, "%s in %s", word[i:i+4], word)
+		}
+	}
+}
+
+func TestTrainSaveLoad(t *testing.T) {
+	m, err := langmodel.Train("test", strings.NewReader("abcd abcd abce"))
+	assert.NoError(t, err)
+	assert.Greater(t, m.Quadgram("abcd"), m.Quadgram("abce"))
+	assert.Greater(t, m.Quadgram("abce"), m.Quadgram("zzzz"))
+
+	var buf bytes.Buffer
+	assert.NoError(t, m.Save(&buf))
+	loaded, err := langmodel.Load(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "test", loaded.Name)
+	for _, n := range langmodel.Orders {
+		assert.Equal(t, m.LogProb("abcdx", n), loaded.LogProb("abcdx", n))
+	}
+
+	file := filepath.Join(t.TempDir(), "test.json")
+	assert.NoError(t, m.SaveFile(file))
+	loaded, err = langmodel.Lookup(file)
+	assert.NoError(t, err)
+	assert.Equal(t, m.Bigram("abc"), loaded.Bigram("abc"))
+
+	_, err = langmodel.Train("empty", strings.NewReader("123 !?"))
+	assert.ErrorIs(t, err, langmodel.ErrEmptyCorpus)
+
+	_, err = langmodel.Load(strings.NewReader(`{"name":"bad","counts":{"1":{"A":1},"2":{},"4":{}}}`))
+	assert.Error(t, err)
+}
+
+func TestCrackWith(t *testing.T) {
+	pt, _ := langmodel.Builtin(langmodel.Portuguese)
+	ciphertext := crypto.EncryptText(portuguese, 11)
+
+	candidates := crypto.CrackWith(ciphertext, pt.Score)
+	assert.Len(t, candidates, 26)
+	assert.Equal(t, 11, candidates[0].Places)
+	assert.Equal(t, portuguese, candidates[0].Text)
+}
+
+func TestBuiltinPortugueseCoverage(t *testing.T) {
+	pt, _ := langmodel.Builtin(langmodel.Portuguese)
+	floor := pt.Quadgram("qzxj")
+
+	words := "porque quando sempre depois cidade trabalho coisas pessoas governo conhecimento " +
+		"criancas pergunta manha noite janela cozinha caminho verdade lembranca historia " +
+		"primeiro segundo alguns nenhum muito tambem ainda entre contra durante"
+	for _, word := range strings.Fields(words) {
+		for i := 0; i+4 <= len(word); i++ {
+			assert.Greater(t, pt.Quadgram(word[i:i+4]), floor, "%s in %s", word[i:i+4], word)
+		}
+	}
+}
+
+func TestBuiltinPortugueseShifts(t *testing.T) {
+	pt, _ := langmodel.Builtin(langmodel.Portuguese)
+	sentences := []string{
+		"a menina comprou pao na padaria",
+		"o rio enche quando chove na serra",
+		"meu avo contava historias do sertao",
+		"fechei a janela antes da chuva",
+		"ela voltou para casa cansada",
+		"os meninos jogavam bola na rua",
+		"amanha vamos visitar a fazenda",
+		"nao esqueca de regar as plantas",
+	}
+	for _, sentence := range sentences {
+		for _, places := range []int{1, 3, 7, 13, 21, 25} {
+			candidates := crypto.CrackWith(crypto.EncryptText(sentence, places), pt.Score)
+			assert.Equal(t, places, candidates[0].Places, "%q shifted by %d", sentence, places)
+			assert.Equal(t, sentence, candidates[0].Text, "%q shifted by %d", sentence, places)
+		}
+	}
+}
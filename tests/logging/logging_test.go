@@ -0,0 +1,116 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wesleyholiveira/caesar-challenge/config"
+	"github.com/wesleyholiveira/caesar-challenge/logging"
+	"github.com/wesleyholiveira/caesar-challenge/request"
+)
+
+func TestRedact(t *testing.T) {
+	config.TokenCodeNation = "s3cr3t"
+	t.Cleanup(func() { config.TokenCodeNation = "" })
+
+	assert.Equal(t, "https://api/generate-data?token=********&x=1", logging.Redact("https://api/generate-data?token=abc&x=1"))
+	assert.Equal(t, "key ******** leaked", logging.Redact("key s3cr3t leaked"))
+	assert.Equal(t, "nothing here", logging.Redact("nothing here"))
+}
+
+func TestNew(t *testing.T) {
+	config.TokenCodeNation = "s3cr3t"
+	t.Cleanup(func() { config.TokenCodeNation = "" })
+
+	buf := new(bytes.Buffer)
+	logger, err := logging.New(buf, slog.LevelInfo, logging.JSON)
+	assert.NoError(t, err)
+
+	logger = logger.With(logging.RunIDKey, "run-1")
+	logger.Debug("hidden")
+	logger.Info("calling ?token=s3cr3t", "token", "answer-token", "url", "http://x/?token=abc",
+		"error", errors.New(`Get "http://x/?token=s3cr3t": refused`), slog.Group("req", "token", "t"))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 1)
+	assert.NotContains(t, lines[0], "s3cr3t")
+	assert.NotContains(t, lines[0], "answer-token")
+	assert.NotContains(t, lines[0], "abc")
+
+	line := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal([]byte(lines[0]), &line))
+	assert.Equal(t, "run-1", line[logging.RunIDKey])
+	assert.Equal(t, "calling ?token=********", line["msg"])
+	assert.Equal(t, logging.Mask, line["token"])
+	assert.Equal(t, `Get "http://x/?token=********": refused`, line["error"])
+	assert.Equal(t, map[string]interface{}{"token": logging.Mask}, line["req"])
+
+	_, err = logging.New(buf, slog.LevelInfo, "xml")
+	assert.Error(t, err)
+}
+
+func TestParseLevel(t *testing.T) {
+	for s, level := range map[string]slog.Level{"": slog.LevelInfo, "debug": slog.LevelDebug, "WARN": slog.LevelWarn, "error": slog.LevelError} {
+		l, err := logging.ParseLevel(s)
+		assert.NoError(t, err)
+		assert.Equal(t, level, l)
+	}
+
+	_, err := logging.ParseLevel("loud")
+	assert.Error(t, err)
+}
+
+func TestRequestLogging(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	buf := new(bytes.Buffer)
+	logger, _ := logging.New(buf, slog.LevelDebug, logging.Text)
+	body, err := request.NewGetRequest(context.Background(), logger)(server.URL + "/generate-data?token=abc")
+	assert.NoError(t, err)
+	assert.Equal(t, "{}", string(body))
+	assert.Contains(t, buf.String(), `msg="request done" method=GET`)
+	assert.Contains(t, buf.String(), "status=200")
+	assert.NotContains(t, buf.String(), "token=abc")
+
+	buf.Reset()
+	_, err = request.NewPostRequest(context.Background(), logger)("http://127.0.0.1:0/?token=abc", new(bytes.Buffer))
+	assert.Error(t, err)
+	assert.Contains(t, buf.String(), "level=ERROR")
+	assert.NotContains(t, buf.String(), "abc")
+
+	_, err = request.NewGetRequest(context.Background(), nil)(server.URL)
+	assert.NoError(t, err)
+}
+
+func TestDefaultRedacts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	buf := new(bytes.Buffer)
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(buf, nil)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	_, err := request.NewGetRequest(context.Background(), logging.Default())(server.URL + "/generate-data?token=SECRET123")
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "token="+logging.Mask)
+	assert.NotContains(t, buf.String(), "SECRET123")
+
+	buf.Reset()
+	logging.Default().With("token", "t1").WithGroup("req").Info("calling ?token=SECRET123", "url", "http://x/?token=SECRET123")
+	assert.Equal(t, 0, strings.Count(buf.String(), "SECRET123")+strings.Count(buf.String(), "t1"))
+	assert.Contains(t, buf.String(), `req.url="http://x/?token=`+logging.Mask)
+}
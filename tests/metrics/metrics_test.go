@@ -0,0 +1,123 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/wesleyholiveira/caesar-challenge/batch"
+	"github.com/wesleyholiveira/caesar-challenge/config"
+	"github.com/wesleyholiveira/caesar-challenge/crypto"
+	"github.com/wesleyholiveira/caesar-challenge/metrics"
+	"github.com/wesleyholiveira/caesar-challenge/request"
+)
+
+func TestEndpoint(t *testing.T) {
+	assert.Equal(t, "generate-data", metrics.Endpoint("https://api.codenation.dev/v1/challenge/dev-ps/generate-data?token=x"))
+	assert.Equal(t, "submit-solution", metrics.Endpoint("/submit-solution"))
+	assert.Equal(t, "unknown", metrics.Endpoint("%zz"))
+}
+
+func TestCipherCounters(t *testing.T) {
+	decrypted := testutil.ToFloat64(metrics.DecryptedBytes)
+	attempts := testutil.ToFloat64(metrics.CrackAttempts)
+
+	crypto.DecryptText("khoor", 3)
+	crypto.Crack("khoor")
+	assert.Equal(t, decrypted, testutil.ToFloat64(metrics.DecryptedBytes))
+	assert.Equal(t, attempts, testutil.ToFloat64(metrics.CrackAttempts))
+
+	dec, _ := batch.NewDecoder(strings.NewReader(`{"cifrado":"khoor"}`+"\n"), batch.JSONL)
+	_, err := batch.Process(dec, 1, func(batch.Result) error { return nil })
+	assert.NoError(t, err)
+	assert.Equal(t, decrypted+5, testutil.ToFloat64(metrics.DecryptedBytes))
+	assert.Equal(t, attempts+26, testutil.ToFloat64(metrics.CrackAttempts))
+}
+
+func TestRequestRetries(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	config.MaxRetries, config.RetryDelay = 2, time.Millisecond
+	t.Cleanup(func() { config.MaxRetries, config.RetryDelay = 0, time.Second })
+
+	retries := testutil.ToFloat64(metrics.Retries.WithLabelValues("retried"))
+	ok := testutil.ToFloat64(metrics.Requests.WithLabelValues("retried", "200"))
+	failed := testutil.ToFloat64(metrics.Requests.WithLabelValues("retried", "502"))
+
+	body, err := request.NewGetRequest(context.Background(), nil)(server.URL + "/retried")
+	assert.NoError(t, err)
+	assert.Equal(t, "{}", string(body))
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, retries+1, testutil.ToFloat64(metrics.Retries.WithLabelValues("retried")))
+	assert.Equal(t, ok+1, testutil.ToFloat64(metrics.Requests.WithLabelValues("retried", "200")))
+	assert.Equal(t, failed+1, testutil.ToFloat64(metrics.Requests.WithLabelValues("retried", "502")))
+
+	calls = 0
+	_, err = request.NewPostRequest(context.Background(), nil)(server.URL+"/posted", new(bytes.Buffer))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestRetryHonorsContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	config.MaxRetries, config.RetryDelay = 3, time.Hour
+	t.Cleanup(func() { config.MaxRetries, config.RetryDelay = 0, time.Second })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := request.NewGetRequest(ctx, nil)(server.URL + "/cancelled")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 10*time.Second)
+}
+
+func TestExport(t *testing.T) {
+	metrics.ObserveRequest("http://x/generate-data", http.MethodGet, 200, 30*time.Millisecond)
+
+	file := filepath.Join(t.TempDir(), "metrics.prom")
+	assert.NoError(t, metrics.WriteFile(file))
+	data, _ := os.ReadFile(file)
+	assert.Contains(t, string(data), `caesar_request_duration_seconds_count{endpoint="generate-data",method="GET"}`)
+	assert.Contains(t, string(data), `caesar_requests_total{endpoint="generate-data",status="200"}`)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() { served <- metrics.Serve(ctx, l) }()
+
+	resp, err := http.Get("http://" + l.Addr().String() + "/metrics")
+	assert.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Contains(t, string(body), "caesar_decrypted_bytes_total")
+
+	cancel()
+	assert.NoError(t, <-served)
+}
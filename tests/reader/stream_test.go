@@ -0,0 +1,105 @@
+package reader
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wesleyholiveira/caesar-challenge/reader"
+)
+
+func gzipped(t *testing.T, data string) []byte {
+	buf := new(bytes.Buffer)
+	zw := gzip.NewWriter(buf)
+	if _, err := zw.Write([]byte(data)); err != nil {
+		t.Fatal(err)
+	}
+	zw.Close()
+
+	return buf.Bytes()
+}
+
+func TestReadLimit(t *testing.T) {
+	data, err := reader.ReadLimit(iotest.OneByteReader(strings.NewReader("short reads")), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "short reads", string(data))
+
+	data, err = reader.ReadLimit(strings.NewReader("12345"), 5)
+	assert.NoError(t, err)
+	assert.Equal(t, "12345", string(data))
+
+	_, err = reader.ReadLimit(strings.NewReader("123456"), 5)
+	assert.True(t, errors.Is(err, reader.ErrTooLarge))
+
+	_, err = reader.ReadLimit(iotest.ErrReader(errors.New("broken pipe")), 0)
+	assert.Error(t, err)
+}
+
+func TestDecompress(t *testing.T) {
+	r, err := reader.Decompress(bytes.NewReader(gzipped(t, "compressed")))
+	assert.NoError(t, err)
+	data, _ := io.ReadAll(r)
+	assert.Equal(t, "compressed", string(data))
+
+	r, err = reader.Decompress(strings.NewReader("plain"))
+	assert.NoError(t, err)
+	data, _ = io.ReadAll(r)
+	assert.Equal(t, "plain", string(data))
+
+	r, err = reader.Decompress(strings.NewReader(""))
+	assert.NoError(t, err)
+	data, _ = io.ReadAll(r)
+	assert.Empty(t, data)
+}
+
+func TestReadFile(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "answer.json.gz")
+	os.WriteFile(file, gzipped(t, `{"numero_casas":1}`), 0644)
+
+	data, err := reader.ReadFile(file, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"numero_casas":1}`, string(data))
+
+	_, err = reader.ReadFile(file, 4)
+	assert.True(t, errors.Is(err, reader.ErrTooLarge))
+
+	r, err := reader.ReadChallenge(file, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, r.Places)
+
+	answer, err := reader.ReadAnswerLimit(file, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "answer.json.gz", answer.Info.Name())
+
+	_, err = reader.ReadAnswerLimit(dir, 0)
+	assert.Error(t, err)
+}
+
+func TestReadStdin(t *testing.T) {
+	pr, pw, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stdin := os.Stdin
+	os.Stdin = pr
+	defer func() { os.Stdin = stdin }()
+
+	go func() {
+		pw.Write([]byte("from a pipe"))
+		pw.Close()
+	}()
+
+	answer, err := reader.ReadAnswer(reader.Stdin)
+	assert.NoError(t, err)
+	assert.Equal(t, "from a pipe", string(answer.Data))
+	assert.Equal(t, int64(0), answer.Info.Size())
+}
@@ -0,0 +1,86 @@
+package reader
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wesleyholiveira/caesar-challenge/crypto"
+	"github.com/wesleyholiveira/caesar-challenge/model"
+	"github.com/wesleyholiveira/caesar-challenge/reader"
+)
+
+func validAnswer() *model.ChallengeResponse {
+	return &model.ChallengeResponse{
+		Places:        3,
+		Token:         "token",
+		CryptedText:   "d oljhlud udsrvd 123.",
+		DecryptedText: "a ligeira raposa 123.",
+		SummaryCrypto: "9639a337c7173a4eb66a303b220af33c15bfec38",
+	}
+}
+
+func fields(problems []reader.Problem) []string {
+	names := []string{}
+	for _, p := range problems {
+		names = append(names, p.Field)
+	}
+
+	return names
+}
+
+func TestValidate(t *testing.T) {
+	assert.Empty(t, reader.Validate(validAnswer()))
+
+	t.Run("missing fields", func(t *testing.T) {
+		assert.Equal(t, []string{"token", "cifrado", "decifrado", "resumo_criptografico", "numero_casas"}, fields(reader.Validate(&model.ChallengeResponse{})))
+	})
+
+	t.Run("places out of range", func(t *testing.T) {
+		r := validAnswer()
+		r.Places = 26
+		assert.Contains(t, fields(reader.Validate(r)), "numero_casas")
+	})
+
+	t.Run("wrong summary", func(t *testing.T) {
+		r := validAnswer()
+		r.SummaryCrypto = "a9993e364706816aba3e25717850c26c9cd0d89d"
+		assert.Equal(t, []string{"resumo_criptografico"}, fields(reader.Validate(r)))
+	})
+
+	t.Run("wrong decryption", func(t *testing.T) {
+		r := validAnswer()
+		r.Places = 4
+		assert.Contains(t, fields(reader.Validate(r)), "decifrado")
+	})
+
+	t.Run("pipeline", func(t *testing.T) {
+		r := &model.ChallengeResponse{Places: 47, Token: "t", CryptedText: "w6==@[ (@C=5P", Pipeline: "caesar:47,printable",
+			DecryptedText: "hello, world!", SummaryCrypto: crypto.Summary("hello, world!")}
+		assert.Empty(t, reader.Validate(r))
+
+		r.Pipeline = "caesar:46,printable"
+		assert.Equal(t, []string{"decifrado"}, fields(reader.Validate(r)))
+
+		r.Pipeline = "rot13"
+		assert.Equal(t, []string{"pipeline"}, fields(reader.Validate(r)))
+	})
+}
+
+func TestReadValidChallenge(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "answer.json")
+	os.WriteFile(file, []byte(`{"numero_casas":1,"token":"t","cifrado":"bcd","decifrado":"abc","resumo_criptografico":"a9993e364706816aba3e25717850c26c9cd0d89d"}`), 0644)
+
+	r, problems, err := reader.ReadValidChallenge(file, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, problems)
+	assert.Equal(t, "abc", r.DecryptedText)
+
+	_, _, err = reader.ReadValidChallenge(filepath.Join(t.TempDir(), "missing.json"), nil)
+	assert.Error(t, err)
+
+	os.WriteFile(file, []byte(`not json`), 0644)
+	_, _, err = reader.ReadValidChallenge(file, nil)
+	assert.Error(t, err)
+}
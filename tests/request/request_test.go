@@ -1,4 +1,3 @@
-```go
 package request
 
 import (
@@ -6,102 +5,61 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/wesleyholiveira/caesar-challenge/config"
+	"github.com/wesleyholiveira/caesar-challenge/reader"
+	"github.com/wesleyholiveira/caesar-challenge/request"
 )
 
-func TestGetRequest(t *testing.T) {
-	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
-		rw.Write([]byte(`OK`))
+const challenge = `{"numero_casas":3,"token":"token","cifrado":"khoor","decifrado":"","resumo_criptografico":""}`
+
+func TestGetCryptedText(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "tk", r.URL.Query().Get("token"))
+		w.Write([]byte(challenge))
 	}))
 	defer server.Close()
 
-	// Test with a valid URL
-	body, err := getRequest(server.URL)
-	assert.NoError(t, err)
-	assert.Equal(t, []byte(`OK`), body)
-
-	// Test with an invalid URL
-	_, err = getRequest("invalid_url")
-	assert.Error(t, err)
-
-	// Test with a URL that leads to a server that is down or unreachable
-	server.Close()
-	_, err = getRequest(server.URL)
-	assert.Error(t, err)
-}
-
-func TestParseResponse(t *testing.T) {
-	// Test with a valid JSON body
-	validJSON := []byte(`{"numero_casas": 10, "token": "token", "cifrado": "crypted", "decifrado": "decrypted", "resumo_criptografico": "summary"}`)
-	response, err := parseResponse(validJSON)
-	assert.NoError(t, err)
-	assert.Equal(t, &ChallengeResponse{Places: 10, Token: "token", CryptedText: "crypted", DecryptedText: "decrypted", SummaryCrypto: "summary"}, response)
+	config.GenerateUrl, config.TokenCodeNation = server.URL+"/generate-data", "tk"
+	t.Cleanup(func() { config.GenerateUrl, config.TokenCodeNation = "", "" })
 
-	// Test with an invalid JSON body
-	invalidJSON := []byte(`invalid_json`)
-	_, err = parseResponse(invalidJSON)
-	assert.Error(t, err)
-
-	// Test with a JSON body that is missing some fields
-	missingFieldsJSON := []byte(`{"numero_casas": 10, "token": "token"}`)
-	response, err = parseResponse(missingFieldsJSON)
+	file := filepath.Join(t.TempDir(), "answer.json")
+	w, err := request.GetCryptedText(file, nil, nil)
 	assert.NoError(t, err)
-	assert.Equal(t, &ChallengeResponse{Places: 10, Token: "token"}, response)
-}
+	assert.Equal(t, "khoor", w.Response.(*request.ChallengeResponse).CryptedText)
 
-func TestGetCryptedText(t *testing.T) {
-	// Test with a valid file name, and valid `getRequest` and `parseResponse` functions
-	_, err := GetCryptedText("valid_file", getRequest, parseResponse)
+	response, err := reader.ReadChallenge(file, nil)
 	assert.NoError(t, err)
+	assert.Equal(t, 3, response.Places)
 
-	// Test with an invalid file name
-	_, err = GetCryptedText("", getRequest, parseResponse)
+	_, err = request.GetCryptedText(file, func(string) ([]byte, error) { return []byte("invalid"), nil }, nil)
 	assert.Error(t, err)
 
-	// Test with `getRequest` or `parseResponse` functions that return an error
-	getRequestError := func(string) ([]byte, error) { return nil, errors.New("getRequest error") }
-	parseResponseError := func([]byte) (*ChallengeResponse, error) { return nil, errors.New("parseResponse error") }
-	_, err = GetCryptedText("valid_file", getRequestError, parseResponse)
-	assert.Error(t, err)
-	_, err = GetCryptedText("valid_file", getRequest, parseResponseError)
-	assert.Error(t, err)
+	_, err = request.GetCryptedText(file, func(string) ([]byte, error) { return nil, errors.New("offline") }, nil)
+	assert.EqualError(t, err, "offline")
 }
 
-func TestPostRequest(t *testing.T) {
-	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
-		rw.Write([]byte(`OK`))
-	}))
-	defer server.Close()
-
-	// Test with a valid URL and body
-	body, err := postRequest(server.URL, bytes.NewBuffer([]byte(`body`)))
+func TestPostSubmitData(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "answer.json")
+	_, err := request.GetCryptedText(file, func(string) ([]byte, error) { return []byte(challenge), nil }, nil)
 	assert.NoError(t, err)
-	assert.Equal(t, []byte(`OK`), body)
 
-	// Test with an invalid URL
-	_, err = postRequest("invalid_url", bytes.NewBuffer([]byte(`body`)))
-	assert.Error(t, err)
-
-	// Test with a URL that leads to a server that is down or unreachable
-	server.Close()
-	_, err = postRequest(server.URL, bytes.NewBuffer([]byte(`body`)))
-	assert.Error(t, err)
-}
-
-func TestPostSubmitData(t *testing.T) {
-	// Test with a valid file name and `postRequest` function
-	_, err := PostSubmitData("valid_file", postRequest)
+	var body string
+	resp, err := request.PostSubmitData(file, func(url string, b *bytes.Buffer) ([]byte, error) {
+		body = b.String()
+		return []byte(`{"score":100}`), nil
+	})
 	assert.NoError(t, err)
+	assert.Equal(t, `{"score":100}`, string(resp))
+	assert.Contains(t, body, `name="answer"; filename="answer.json"`)
+	assert.Contains(t, body, `"cifrado":"khoor"`)
 
-	// Test with an invalid file name
-	_, err = PostSubmitData("", postRequest)
+	_, err = request.PostSubmitData(filepath.Join(t.TempDir(), "missing.json"), nil)
 	assert.Error(t, err)
 
-	// Test with a `postRequest` function that returns an error
-	postRequestError := func(string, *bytes.Buffer) ([]byte, error) { return nil, errors.New("postRequest error") }
-	_, err = PostSubmitData("valid_file", postRequestError)
-	assert.Error(t, err)
+	_, err = request.PostSubmitData(file, func(string, *bytes.Buffer) ([]byte, error) { return nil, errors.New("refused") })
+	assert.EqualError(t, err, "refused")
 }
-```
\ No newline at end of file
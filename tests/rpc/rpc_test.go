@@ -0,0 +1,212 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/wesleyholiveira/caesar-challenge/rpc"
+	"github.com/wesleyholiveira/caesar-challenge/rpc/cipherpb"
+	"github.com/wesleyholiveira/caesar-challenge/runner"
+)
+
+const challenge = `{"numero_casas":3,"token":"token","cifrado":"wkh txlfn eurzq ira mxpsv ryhu wkh odcb grj.","decifrado":"","resumo_criptografico":""}`
+
+func dial(t *testing.T, s *rpc.Server) cipherpb.CipherClient {
+	l := bufconn.Listen(1 << 20)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- s.Serve(ctx, l) }()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return l.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		conn.Close()
+		cancel()
+		<-done
+	})
+
+	return cipherpb.NewCipherClient(conn)
+}
+
+func newServer(t *testing.T) (*rpc.Server, *[]string) {
+	submitted := []string{}
+	s := rpc.New(filepath.Join(t.TempDir(), "answer.json"))
+	s.GetRequest = func(string) ([]byte, error) { return []byte(challenge), nil }
+	s.PostRequest = func(url string, body *bytes.Buffer) ([]byte, error) {
+		submitted = append(submitted, body.String())
+		return []byte(`{"score":100}`), nil
+	}
+
+	return s, &submitted
+}
+
+func TestCipher(t *testing.T) {
+	s, _ := newServer(t)
+	client := dial(t, s)
+	ctx := context.Background()
+
+	resp, err := client.Encrypt(ctx, &cipherpb.TextRequest{Text: "abc", Places: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, "bcd", resp.GetText())
+
+	resp, err = client.Decrypt(ctx, &cipherpb.TextRequest{Text: "bcd", Places: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", resp.GetText())
+	assert.Equal(t, "a9993e364706816aba3e25717850c26c9cd0d89d", resp.GetSummary())
+
+	resp, err = client.Decrypt(ctx, &cipherpb.TextRequest{Text: "wkh txlfn eurzq ira mxpsv ryhu wkh odcb grj"})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), resp.GetPlaces())
+
+	crack, err := client.Crack(ctx, &cipherpb.CrackRequest{Text: "wkh txlfn eurzq ira mxpsv ryhu wkh odcb grj", Top: 3})
+	assert.NoError(t, err)
+	assert.Len(t, crack.GetCandidates(), 3)
+	assert.Equal(t, "the quick brown fox jumps over the lazy dog", crack.GetCandidates()[0].GetText())
+
+	_, err = client.Crack(ctx, &cipherpb.CrackRequest{Text: "abc", Top: -1})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestCipherRequiresText(t *testing.T) {
+	s, _ := newServer(t)
+	client := dial(t, s)
+	ctx := context.Background()
+
+	_, err := client.Encrypt(ctx, &cipherpb.TextRequest{Text: "abc"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Contains(t, status.Convert(err).Message(), "places is required")
+
+	_, err = client.Encrypt(ctx, &cipherpb.TextRequest{Places: 3})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, "text is required", status.Convert(err).Message())
+
+	_, err = client.Decrypt(ctx, &cipherpb.TextRequest{Places: 3})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.Crack(ctx, &cipherpb.CrackRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func collect(t *testing.T, client cipherpb.CipherClient, req *cipherpb.RunChallengeRequest) ([]*cipherpb.RunChallengeProgress, error) {
+	stream, err := client.RunChallenge(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	messages := []*cipherpb.RunChallengeProgress{}
+	for {
+		msg, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return messages, nil
+		}
+
+		if err != nil {
+			return messages, err
+		}
+
+		messages = append(messages, msg)
+	}
+}
+
+func TestRunChallenge(t *testing.T) {
+	s, submitted := newServer(t)
+	client := dial(t, s)
+
+	messages, err := collect(t, client, &cipherpb.RunChallengeRequest{})
+	assert.NoError(t, err)
+	assert.Len(t, messages, 4)
+	assert.Equal(t, cipherpb.Step_STEP_FETCH, messages[0].GetStep())
+	assert.Equal(t, cipherpb.Step_STEP_DECRYPT, messages[1].GetStep())
+	assert.Equal(t, "the quick brown fox jumps over the lazy dog.", messages[1].GetDecryptedText())
+	assert.Equal(t, cipherpb.Step_STEP_VERIFY, messages[2].GetStep())
+	assert.Empty(t, messages[2].GetDiffs())
+	assert.Equal(t, cipherpb.Step_STEP_SUBMIT, messages[3].GetStep())
+	assert.Equal(t, `{"score":100}`, string(messages[3].GetResponse()))
+	assert.Len(t, *submitted, 1)
+
+	messages, err = collect(t, client, &cipherpb.RunChallengeRequest{Load: true})
+	assert.Empty(t, messages)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	messages, err = collect(t, client, &cipherpb.RunChallengeRequest{DryRun: true})
+	assert.NoError(t, err)
+	assert.Equal(t, cipherpb.Step_STEP_DRY_RUN, messages[len(messages)-1].GetStep())
+	assert.Contains(t, messages[len(messages)-1].GetPayload(), "the quick brown fox")
+	assert.Len(t, *submitted, 1)
+
+	s.GetRequest = func(string) ([]byte, error) { return nil, errors.New("offline") }
+	messages, err = collect(t, client, &cipherpb.RunChallengeRequest{})
+	assert.Empty(t, messages)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+
+	// the dry run fetched a new challenge, which was never submitted
+	messages, err = collect(t, client, &cipherpb.RunChallengeRequest{Load: true})
+	assert.NoError(t, err)
+	assert.Equal(t, cipherpb.Step_STEP_SUBMIT, messages[len(messages)-1].GetStep())
+	assert.Len(t, *submitted, 2)
+
+	os.Remove(runner.StateFile(s.AnswerFile))
+	os.WriteFile(s.AnswerFile, []byte(`{"numero_casas":0,"cifrado":"abc"}`), 0644)
+	messages, err = collect(t, client, &cipherpb.RunChallengeRequest{Load: true})
+	assert.Len(t, messages, 2)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestRunChallengeResubmit(t *testing.T) {
+	s, submitted := newServer(t)
+	fetched := 0
+	s.GetRequest = func(string) ([]byte, error) {
+		fetched++
+		return []byte(challenge), nil
+	}
+	client := dial(t, s)
+
+	_, err := collect(t, client, &cipherpb.RunChallengeRequest{})
+	assert.NoError(t, err)
+
+	// a submit interrupted before its response is uncertain
+	assert.NoError(t, runner.WriteState(s.AnswerFile, runner.State{RunID: "run", Step: runner.Submitting}))
+	_, err = collect(t, client, &cipherpb.RunChallengeRequest{})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.Contains(t, status.Convert(err).Message(), runner.ErrSubmitUncertain.Error())
+	assert.Len(t, *submitted, 1)
+
+	messages, err := collect(t, client, &cipherpb.RunChallengeRequest{Resubmit: true})
+	assert.NoError(t, err)
+	assert.Equal(t, cipherpb.Step_STEP_SUBMIT, messages[len(messages)-1].GetStep())
+	assert.Len(t, *submitted, 2)
+	assert.Equal(t, 1, fetched)
+
+	_, err = collect(t, client, &cipherpb.RunChallengeRequest{Load: true})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.Contains(t, status.Convert(err).Message(), runner.ErrAlreadySubmitted.Error())
+
+	_, err = collect(t, client, &cipherpb.RunChallengeRequest{Load: true, Resubmit: true})
+	assert.NoError(t, err)
+	assert.Len(t, *submitted, 3)
+
+	// an interrupted run is resumed unless a fresh one is asked for
+	assert.NoError(t, runner.WriteState(s.AnswerFile, runner.State{RunID: "run", Step: runner.Fetch}))
+	_, err = collect(t, client, &cipherpb.RunChallengeRequest{Fresh: true})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, fetched)
+	assert.Len(t, *submitted, 4)
+}
@@ -0,0 +1,185 @@
+package runner
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wesleyholiveira/caesar-challenge/runner"
+)
+
+const challenge = `{"numero_casas":3,"token":"token","cifrado":"wkh txlfn eurzq ira mxpsv ryhu wkh odcb grj.","decifrado":"","resumo_criptografico":""}`
+
+type fake struct {
+	fetched   int
+	submitted int
+	offline   bool
+}
+
+func (f *fake) options(file string) runner.Options {
+	return runner.Options{
+		File: file,
+		GetRequest: func(string) ([]byte, error) {
+			f.fetched++
+			return []byte(challenge), nil
+		},
+		PostRequest: func(string, *bytes.Buffer) ([]byte, error) {
+			if f.offline {
+				return nil, errors.New("offline")
+			}
+
+			f.submitted++
+			return []byte(`{"score":100}`), nil
+		},
+	}
+}
+
+func TestResume(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "answer.json")
+	f := &fake{offline: true}
+
+	_, err := runner.Run(f.options(file))
+	assert.Error(t, err)
+	state, err := runner.ReadState(file)
+	assert.NoError(t, err)
+	assert.Equal(t, runner.Verify, state.Step)
+
+	f.offline = false
+	steps := []runner.Step{}
+	opts := f.options(file)
+	opts.Progress = func(step runner.Step, _ *runner.Result) { steps = append(steps, step) }
+	result, err := runner.Run(opts)
+	assert.NoError(t, err)
+	assert.Equal(t, state.RunID, result.RunID)
+	assert.Equal(t, runner.Verify, result.Resumed)
+	assert.Equal(t, []runner.Step{runner.Verify, runner.Submit}, steps)
+	assert.Equal(t, 1, f.fetched)
+	assert.Equal(t, 1, f.submitted)
+
+	state, _ = runner.ReadState(file)
+	assert.Equal(t, runner.Submit, state.Step)
+
+	result, err = runner.Run(f.options(file))
+	assert.NoError(t, err)
+	assert.Empty(t, result.Resumed)
+	assert.NotEqual(t, state.RunID, result.RunID)
+	assert.Equal(t, 2, f.fetched)
+	assert.Equal(t, 2, f.submitted)
+}
+
+func TestResumeAfterFetch(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "answer.json")
+	os.WriteFile(file, []byte(challenge), 0644)
+	assert.NoError(t, runner.WriteState(file, runner.State{RunID: "interrupted", Step: runner.Fetch}))
+
+	f := &fake{}
+	result, err := runner.Run(f.options(file))
+	assert.NoError(t, err)
+	assert.Equal(t, "interrupted", result.RunID)
+	assert.Equal(t, "the quick brown fox jumps over the lazy dog.", result.Answer.DecryptedText)
+	assert.Equal(t, 0, f.fetched)
+	assert.Equal(t, 1, f.submitted)
+}
+
+func TestSubmitUncertain(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "answer.json")
+	os.WriteFile(file, []byte(challenge), 0644)
+	assert.NoError(t, runner.WriteState(file, runner.State{RunID: "interrupted", Step: runner.Submitting}))
+
+	f := &fake{}
+	_, err := runner.Run(f.options(file))
+	assert.True(t, errors.Is(err, runner.ErrSubmitUncertain))
+	assert.Equal(t, 0, f.fetched+f.submitted)
+
+	opts := f.options(file)
+	opts.Fresh = true
+	_, err = runner.Run(opts)
+	assert.True(t, errors.Is(err, runner.ErrSubmitUncertain))
+
+	opts = f.options(file)
+	opts.Load = true
+	_, err = runner.Run(opts)
+	assert.True(t, errors.Is(err, runner.ErrSubmitUncertain))
+	assert.Equal(t, 0, f.fetched+f.submitted)
+
+	opts.Resubmit = true
+	result, err := runner.Run(opts)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, f.fetched)
+	assert.Equal(t, 1, f.submitted)
+
+	assert.NotEqual(t, "interrupted", result.RunID)
+
+	assert.NoError(t, runner.WriteState(file, runner.State{RunID: "interrupted", Step: runner.Submitting}))
+	opts = f.options(file)
+	opts.Resubmit = true
+	result, err = runner.Run(opts)
+	assert.NoError(t, err)
+	assert.Equal(t, "interrupted", result.RunID)
+	assert.Equal(t, runner.Verify, result.Resumed)
+	assert.Equal(t, 0, f.fetched)
+	assert.Equal(t, 2, f.submitted)
+}
+
+func TestLoadAlreadySubmitted(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "answer.json")
+	os.WriteFile(file, []byte(challenge), 0644)
+	assert.NoError(t, runner.WriteState(file, runner.State{RunID: "done", Step: runner.Submit}))
+
+	f := &fake{}
+	opts := f.options(file)
+	opts.Load = true
+	_, err := runner.Run(opts)
+	assert.True(t, errors.Is(err, runner.ErrAlreadySubmitted))
+	assert.Equal(t, 0, f.submitted)
+
+	opts.Resubmit = true
+	_, err = runner.Run(opts)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, f.fetched)
+	assert.Equal(t, 1, f.submitted)
+}
+
+func TestDryRunKeepsNoState(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "answer.json")
+	f := &fake{}
+	opts := f.options(file)
+	opts.DryRun = true
+
+	result, err := runner.Run(opts)
+	assert.NoError(t, err)
+	assert.NotNil(t, result.Payload)
+
+	state, err := runner.ReadState(file)
+	assert.NoError(t, err)
+	assert.Nil(t, state)
+	assert.Equal(t, 0, f.submitted)
+}
+
+func TestDryRunAfterSubmit(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "answer.json")
+	f := &fake{}
+	_, err := runner.Run(f.options(file))
+	assert.NoError(t, err)
+
+	opts := f.options(file)
+	opts.DryRun = true
+	_, err = runner.Run(opts)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, f.fetched)
+
+	// the dry run replaced the submitted answer with a new challenge
+	state, err := runner.ReadState(file)
+	assert.NoError(t, err)
+	assert.Nil(t, state)
+
+	opts = f.options(file)
+	opts.Load = true
+	_, err = runner.Run(opts)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, f.fetched)
+	assert.Equal(t, 2, f.submitted)
+}
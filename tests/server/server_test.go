@@ -0,0 +1,127 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wesleyholiveira/caesar-challenge/server"
+)
+
+func post(t *testing.T, h http.Handler, path, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)))
+	return rec
+}
+
+func TestEndpoints(t *testing.T) {
+	h := server.New().Handler()
+
+	rec := post(t, h, "/v1/encrypt", `{"text":"abc","places":1}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"text":"bcd","places":1}`, rec.Body.String())
+
+	rec = post(t, h, "/v1/decrypt", `{"text":"bcd","places":1}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"text":"abc","places":1,"summary":"a9993e364706816aba3e25717850c26c9cd0d89d"}`, rec.Body.String())
+
+	rec = post(t, h, "/v1/decrypt", `{"text":"wkh txlfn eurzq ira mxpsv ryhu wkh odcb grj"}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"places":3`)
+
+	rec = post(t, h, "/v1/crack", `{"text":"wkh txlfn eurzq ira mxpsv ryhu wkh odcb grj","top":2}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	resp := server.CrackResponse{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Len(t, resp.Candidates, 2)
+	assert.Equal(t, "the quick brown fox jumps over the lazy dog", resp.Candidates[0].Text)
+
+	rec = post(t, h, "/v1/digest", `{"text":"abc"}`)
+	assert.JSONEq(t, `{"summary":"a9993e364706816aba3e25717850c26c9cd0d89d"}`, rec.Body.String())
+
+	assert.Equal(t, http.StatusBadRequest, post(t, h, "/v1/crack", `{"text":"abc","top":-1}`).Code)
+
+	rec = post(t, h, "/v1/encrypt", `{"text":"abc"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.JSONEq(t, `{"error":"places is required and must not be zero"}`, rec.Body.String())
+	assert.Equal(t, http.StatusBadRequest, post(t, h, "/v1/encrypt", `{"text":"abc","places":0}`).Code)
+
+	for _, path := range []string{"/v1/encrypt", "/v1/decrypt", "/v1/crack", "/v1/digest"} {
+		rec = post(t, h, path, `{"places":1}`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, path)
+		assert.JSONEq(t, `{"error":"text is required"}`, rec.Body.String(), path)
+	}
+
+	rec = post(t, h, "/v1/digest", `{"text":""}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"summary":"da39a3ee5e6b4b0d3255bfef95601890afd80709"}`, rec.Body.String())
+	assert.Equal(t, http.StatusBadRequest, post(t, h, "/v1/digest", `not json`).Code)
+	assert.Equal(t, http.StatusBadRequest, post(t, h, "/v1/digest", `{"unknown":1}`).Code)
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/digest", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	doc := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &doc))
+	assert.Contains(t, doc["paths"], "/v1/crack")
+}
+
+func TestBodyLimit(t *testing.T) {
+	s := server.New()
+	s.MaxBodyBytes = 16
+
+	rec := post(t, s.Handler(), "/v1/digest", `{"text":"`+strings.Repeat("a", 32)+`"}`)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+}
+
+func TestServe(t *testing.T) {
+	s := server.New()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- s.Serve(ctx, l) }()
+
+	url := "http://" + l.Addr().String()
+	assert.Eventually(t, func() bool {
+		resp, err := http.Get(url + "/readyz")
+		if err != nil {
+			return false
+		}
+		resp.Body.Close()
+		return resp.StatusCode == http.StatusOK
+	}, time.Second, 10*time.Millisecond)
+
+	resp, err := http.Get(url + "/healthz")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	resp.Body.Close()
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not shut down")
+	}
+
+	_, err = http.Get(url + "/healthz")
+	assert.Error(t, err)
+}
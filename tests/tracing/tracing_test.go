@@ -0,0 +1,124 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/wesleyholiveira/caesar-challenge/config"
+	"github.com/wesleyholiveira/caesar-challenge/runner"
+	"github.com/wesleyholiveira/caesar-challenge/tracing"
+)
+
+const challenge = `{"numero_casas":3,"token":"token","cifrado":"wkh txlfn eurzq ira mxpsv ryhu wkh odcb grj.","decifrado":"","resumo_criptografico":""}`
+
+func TestRunSpans(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			w.Write([]byte(challenge))
+			return
+		}
+
+		w.Write([]byte(`{"score":100}`))
+	}))
+	defer server.Close()
+
+	config.GenerateUrl, config.SubmitUrl = server.URL+"/generate-data", server.URL+"/submit-solution"
+	t.Cleanup(func() {
+		config.GenerateUrl, config.SubmitUrl = config.BaseUrl+"generate-data", config.BaseUrl+"submit-solution"
+	})
+
+	_, err := runner.RunContext(context.Background(), runner.Options{File: filepath.Join(t.TempDir(), "answer.json")})
+	assert.NoError(t, err)
+
+	spans := exporter.GetSpans()
+	byName := map[string]tracetest.SpanStub{}
+	for _, s := range spans {
+		byName[s.Name] = s
+	}
+
+	for _, name := range []string{"runner.Run", "request.GetCryptedText", "crypto.Decrypt", "writer.WriteAnswer", "verify.File", "request.PostSubmitData", "reader.ReadAnswer", "HTTP GET", "HTTP POST"} {
+		assert.Contains(t, byName, name)
+	}
+
+	root := byName["runner.Run"]
+	assert.False(t, root.Parent.IsValid())
+	for _, s := range spans {
+		assert.Equal(t, root.SpanContext.TraceID(), s.SpanContext.TraceID(), s.Name)
+	}
+
+	assert.Equal(t, byName["request.GetCryptedText"].SpanContext.SpanID(), byName["HTTP GET"].Parent.SpanID())
+	assert.Equal(t, byName["request.PostSubmitData"].SpanContext.SpanID(), byName["HTTP POST"].Parent.SpanID())
+	assert.Equal(t, byName["crypto.Decrypt"].Parent.SpanID(), root.SpanContext.SpanID())
+}
+
+func TestSetup(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	out := new(bytes.Buffer)
+	shutdown, err := tracing.Setup(context.Background(), tracing.Stdout, "", out)
+	assert.NoError(t, err)
+
+	_, span := tracing.Start(context.Background(), "test.Span")
+	tracing.End(span, assert.AnError)
+	assert.NoError(t, shutdown(context.Background()))
+	assert.Contains(t, out.String(), `"Name":"test.Span"`)
+	assert.Contains(t, out.String(), `"Code":"Error"`)
+
+	shutdown, err = tracing.Setup(context.Background(), tracing.None, "", out)
+	assert.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+
+	_, err = tracing.Setup(context.Background(), "zipkin", "", out)
+	assert.Error(t, err)
+}
+
+func TestSetupRedactsToken(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			w.Write([]byte(challenge))
+			return
+		}
+
+		w.Write([]byte(`{"score":100}`))
+	}))
+	defer server.Close()
+
+	config.GenerateUrl, config.SubmitUrl = server.URL+"/generate-data", server.URL+"/submit-solution"
+	config.TokenCodeNation = "SUPERSECRETTOKEN"
+	t.Cleanup(func() {
+		config.GenerateUrl, config.SubmitUrl = config.BaseUrl+"generate-data", config.BaseUrl+"submit-solution"
+		config.TokenCodeNation = ""
+	})
+
+	out := new(bytes.Buffer)
+	shutdown, err := tracing.Setup(context.Background(), tracing.Stdout, "", out)
+	assert.NoError(t, err)
+
+	_, err = runner.RunContext(context.Background(), runner.Options{File: filepath.Join(t.TempDir(), "answer.json")})
+	assert.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+
+	assert.Contains(t, out.String(), `"Name":"HTTP GET"`)
+	assert.Contains(t, out.String(), `"Name":"HTTP POST"`)
+	assert.Contains(t, out.String(), "token=********")
+	assert.NotContains(t, out.String(), "SUPERSECRETTOKEN")
+}
@@ -0,0 +1,86 @@
+package verify
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wesleyholiveira/caesar-challenge/model"
+	"github.com/wesleyholiveira/caesar-challenge/verify"
+)
+
+func TestAnswer(t *testing.T) {
+	r := &model.ChallengeResponse{
+		Token:         "token",
+		Places:        1,
+		CryptedText:   "bcd",
+		DecryptedText: "abc",
+		SummaryCrypto: "a9993e364706816aba3e25717850c26c9cd0d89d",
+	}
+	assert.Empty(t, verify.Answer(r))
+
+	r.DecryptedText = "xyz"
+	diffs := verify.Answer(r)
+	assert.Equal(t, []verify.Diff{
+		{Field: "decifrado", Stored: "xyz", Expected: "abc"},
+		{Field: "resumo_criptografico", Stored: "a9993e364706816aba3e25717850c26c9cd0d89d", Expected: "66b27417d37e024c46526c2f6d358a754fc552f3"},
+	}, diffs)
+
+	r.DecryptedText = "abc"
+	r.SummaryCrypto = ""
+	diffs = verify.Answer(r)
+	assert.Equal(t, []verify.Diff{{Field: "resumo_criptografico", Stored: "", Expected: "a9993e364706816aba3e25717850c26c9cd0d89d"}}, diffs)
+
+	r.SummaryCrypto = "a9993e364706816aba3e25717850c26c9cd0d89d"
+	r.Places = 0
+	diffs = verify.Answer(r)
+	assert.Equal(t, "numero_casas", diffs[0].Field)
+}
+
+func TestAnswerRequiresEveryField(t *testing.T) {
+	r := &model.ChallengeResponse{
+		Places:        1,
+		CryptedText:   "bcd",
+		DecryptedText: "abc",
+		SummaryCrypto: "a9993e364706816aba3e25717850c26c9cd0d89d",
+	}
+	diffs := verify.Answer(r)
+	assert.Equal(t, []verify.Diff{{Field: "token", Message: "is required"}}, diffs)
+	assert.Equal(t, "token: is required", diffs[0].String())
+
+	diffs = verify.Answer(&model.ChallengeResponse{Places: 1})
+	fields := []string{}
+	for _, d := range diffs {
+		fields = append(fields, d.Field)
+	}
+	assert.Equal(t, []string{"token", "cifrado", "decifrado", "resumo_criptografico"}, fields)
+}
+
+func TestFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "answer.json")
+	os.WriteFile(file, []byte(`{"numero_casas":1,"token":"token","cifrado":"bcd","decifrado":"abc","resumo_criptografico":"a9993e364706816aba3e25717850c26c9cd0d89d"}`), 0644)
+
+	report, err := verify.File(file, nil)
+	assert.NoError(t, err)
+	assert.True(t, report.OK())
+	assert.NoError(t, report.Err())
+	assert.Equal(t, "ok", report.String())
+
+	os.WriteFile(file, []byte(`{"numero_casas":2,"token":"token","cifrado":"bcd","decifrado":"abc","resumo_criptografico":"a9993e364706816aba3e25717850c26c9cd0d89d"}`), 0644)
+
+	report, err = verify.File(file, nil)
+	assert.NoError(t, err)
+	assert.False(t, report.OK())
+	assert.True(t, errors.Is(report.Err(), verify.ErrMismatch))
+	assert.Equal(t, []verify.Diff{{Field: "decifrado", Stored: "abc", Expected: "zab"}}, report.Diffs)
+
+	os.WriteFile(file, []byte(`{"numero_casas":1,"cifrado":"bcd","decifrado":"abc","resumo_criptografico":"a9993e364706816aba3e25717850c26c9cd0d89d"}`), 0644)
+	report, err = verify.File(file, nil)
+	assert.NoError(t, err)
+	assert.True(t, errors.Is(report.Err(), verify.ErrMismatch))
+
+	_, err = verify.File(filepath.Join(t.TempDir(), "missing.json"), nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,134 @@
+package watch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wesleyholiveira/caesar-challenge/format"
+	"github.com/wesleyholiveira/caesar-challenge/watch"
+)
+
+// drop writes a settled file into dir
+func drop(t *testing.T, dir, name, content string) {
+	path := filepath.Join(dir, name)
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	old := time.Now().Add(-time.Hour)
+	assert.NoError(t, os.Chtimes(path, old, old))
+}
+
+func TestScan(t *testing.T) {
+	dir := t.TempDir()
+	drop(t, dir, "a.txt", "wkh txlfn eurzq ira mxpsv ryhu wkh odcb grj.\n")
+	drop(t, dir, "b.txt", "")
+	drop(t, dir, ".hidden", "khoor")
+	os.WriteFile(filepath.Join(dir, "fresh.txt"), []byte("khoor"), 0644)
+
+	w := watch.New(dir)
+	w.Settle = time.Minute
+	results, err := w.Scan()
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+
+	assert.NoError(t, results[0].Err)
+	assert.Equal(t, filepath.Join(dir, watch.DoneDir, "a.txt"), results[0].File)
+	assert.Equal(t, filepath.Join(dir, watch.DoneDir, "a.txt.answer.json"), results[0].Answer)
+	data, _ := os.ReadFile(results[0].Answer)
+	assert.Contains(t, string(data), `"numero_casas":3`)
+	assert.Contains(t, string(data), `"decifrado":"the quick brown fox jumps over the lazy dog."`)
+
+	assert.EqualError(t, results[1].Err, "file is empty")
+	assert.Equal(t, filepath.Join(dir, watch.FailedDir, "b.txt"), results[1].File)
+	data, _ = os.ReadFile(results[1].File + watch.ErrorSuffix)
+	assert.Equal(t, "file is empty\n", string(data))
+
+	for _, name := range []string{".hidden", "fresh.txt"} {
+		_, err := os.Stat(filepath.Join(dir, name))
+		assert.NoError(t, err, name)
+	}
+
+	results, err = w.Scan()
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+}
+
+func TestScanFormatAndPlaces(t *testing.T) {
+	dir := t.TempDir()
+	drop(t, dir, "c.txt", "lipps")
+
+	w := watch.New(dir)
+	w.Places = 4
+	w.Format = format.YAML
+	results, err := w.Scan()
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, filepath.Join(dir, watch.DoneDir, "c.txt.answer.yaml"), results[0].Answer)
+	data, _ := os.ReadFile(results[0].Answer)
+	assert.Contains(t, string(data), "decifrado: hello")
+}
+
+func TestScanSameName(t *testing.T) {
+	dir := t.TempDir()
+	w := watch.New(dir)
+	w.Places = 3
+	for i, content := range []string{"khoor", "zruog", "", ""} {
+		drop(t, dir, "msg.txt", content)
+		results, err := w.Scan()
+		assert.NoError(t, err, i)
+		assert.Len(t, results, 1, i)
+	}
+
+	for name, want := range map[string]string{
+		"msg.txt.answer.json":   `"decifrado":"hello"`,
+		"msg-1.txt.answer.json": `"decifrado":"world"`,
+		"msg.txt":               "khoor",
+		"msg-1.txt":             "zruog",
+	} {
+		data, err := os.ReadFile(filepath.Join(dir, watch.DoneDir, name))
+		assert.NoError(t, err, name)
+		assert.Contains(t, string(data), want, name)
+	}
+
+	for _, name := range []string{"msg.txt", "msg.txt" + watch.ErrorSuffix, "msg-1.txt", "msg-1.txt" + watch.ErrorSuffix} {
+		_, err := os.Stat(filepath.Join(dir, watch.FailedDir, name))
+		assert.NoError(t, err, name)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	dir := t.TempDir()
+	drop(t, dir, "e.txt", "khoor")
+
+	for _, places := range []int{-1, 26} {
+		w := watch.New(dir)
+		w.Places = places
+		_, err := w.Scan()
+		assert.ErrorIs(t, err, watch.ErrPlaces, places)
+	}
+
+	_, err := os.Stat(filepath.Join(dir, "e.txt"))
+	assert.NoError(t, err)
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	drop(t, dir, "d.txt", "khoor")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w := watch.New(dir)
+	w.Interval = 10 * time.Millisecond
+	results := []watch.Result{}
+	w.Report = func(r watch.Result) {
+		results = append(results, r)
+		cancel()
+	}
+
+	assert.NoError(t, w.Run(ctx))
+	assert.Len(t, results, 1)
+	assert.NoError(t, results[0].Err)
+
+	assert.Error(t, watch.New(filepath.Join(dir, "missing")).Run(context.Background()))
+}
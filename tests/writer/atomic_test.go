@@ -0,0 +1,194 @@
+package writer
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/wesleyholiveira/caesar-challenge/writer"
+)
+
+// failingFile writes at most limit bytes before failing, or fails on Sync
+type failingFile struct {
+	*os.File
+	limit    int
+	failSync bool
+}
+
+func (f *failingFile) Write(p []byte) (int, error) {
+	if len(p) > f.limit {
+		n, _ := f.File.Write(p[:f.limit])
+		return n, errors.New("disk full")
+	}
+
+	return f.File.Write(p)
+}
+
+func (f *failingFile) Sync() error {
+	if f.failSync {
+		return errors.New("sync failed")
+	}
+
+	return f.File.Sync()
+}
+
+func failingCreate(limit int, failSync bool) func(string, string) (writer.File, error) {
+	return func(dir, pattern string) (writer.File, error) {
+		f, err := os.CreateTemp(dir, pattern)
+		if err != nil {
+			return nil, err
+		}
+
+		return &failingFile{File: f, limit: limit, failSync: failSync}, nil
+	}
+}
+
+func listDir(t *testing.T, dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+
+	return names
+}
+
+func TestWriteAnswerAtomic(t *testing.T) {
+	t.Run("replaces existing answer", func(t *testing.T) {
+		dir := t.TempDir()
+		file := filepath.Join(dir, "answer.json")
+		os.WriteFile(file, []byte(`{"old":true}`), 0644)
+
+		w := writer.New()
+		w.File = file
+		w.Response = map[string]string{"key": "value"}
+
+		if err := writer.WriteAnswer(w); err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+
+		data, _ := os.ReadFile(file)
+		if string(data) != `{"key":"value"}` {
+			t.Errorf("expected {\"key\":\"value\"}, got %s", data)
+		}
+
+		if names := listDir(t, dir); len(names) != 1 {
+			t.Errorf("expected only the answer file, got %v", names)
+		}
+	})
+
+	t.Run("failure mid-write keeps previous answer", func(t *testing.T) {
+		dir := t.TempDir()
+		file := filepath.Join(dir, "answer.json")
+		os.WriteFile(file, []byte(`{"old":true}`), 0644)
+
+		w := writer.New()
+		w.File = file
+		w.Response = map[string]string{"key": "value"}
+		w.CreateTemp = failingCreate(4, false)
+
+		if err := writer.WriteAnswer(w); err == nil {
+			t.Fatal("expected error, got nil")
+		}
+
+		data, _ := os.ReadFile(file)
+		if string(data) != `{"old":true}` {
+			t.Errorf("expected previous answer to survive, got %s", data)
+		}
+
+		if names := listDir(t, dir); len(names) != 1 {
+			t.Errorf("expected temporary file to be removed, got %v", names)
+		}
+	})
+
+	t.Run("failure on sync keeps previous answer", func(t *testing.T) {
+		dir := t.TempDir()
+		file := filepath.Join(dir, "answer.json")
+		os.WriteFile(file, []byte(`{"old":true}`), 0644)
+
+		w := writer.New()
+		w.File = file
+		w.Response = "test"
+		w.CreateTemp = failingCreate(1<<20, true)
+
+		if err := writer.WriteAnswer(w); err == nil {
+			t.Fatal("expected error, got nil")
+		}
+
+		data, _ := os.ReadFile(file)
+		if string(data) != `{"old":true}` {
+			t.Errorf("expected previous answer to survive, got %s", data)
+		}
+
+		if names := listDir(t, dir); len(names) != 1 {
+			t.Errorf("expected temporary file to be removed, got %v", names)
+		}
+	})
+
+	t.Run("failure mid-write without previous answer", func(t *testing.T) {
+		dir := t.TempDir()
+		file := filepath.Join(dir, "answer.json")
+
+		w := writer.New()
+		w.File = file
+		w.Response = "test"
+		w.CreateTemp = failingCreate(2, false)
+
+		if err := writer.WriteAnswer(w); err == nil {
+			t.Fatal("expected error, got nil")
+		}
+
+		if _, err := os.Stat(file); !os.IsNotExist(err) {
+			t.Errorf("expected no answer file, got %v", err)
+		}
+	})
+
+	t.Run("keeps backup of previous answer", func(t *testing.T) {
+		dir := t.TempDir()
+		file := filepath.Join(dir, "answer.json")
+		os.WriteFile(file, []byte(`{"old":true}`), 0644)
+
+		w := writer.New()
+		w.File = file
+		w.Response = "new"
+		w.Backup = true
+
+		if err := writer.WriteAnswer(w); err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+
+		backup, _ := os.ReadFile(file + writer.BackupSuffix)
+		if string(backup) != `{"old":true}` {
+			t.Errorf("expected backup to hold previous answer, got %s", backup)
+		}
+
+		data, _ := os.ReadFile(file)
+		if string(data) != `"new"` {
+			t.Errorf("expected \"new\", got %s", data)
+		}
+	})
+
+	t.Run("rename failure", func(t *testing.T) {
+		dir := t.TempDir()
+		file := filepath.Join(dir, "answer.json")
+		os.Mkdir(file, 0755)
+		os.WriteFile(filepath.Join(file, "keep"), nil, 0644)
+
+		w := writer.New()
+		w.File = file
+		w.Response = "test"
+
+		if err := writer.WriteAnswer(w); err == nil {
+			t.Fatal("expected error, got nil")
+		}
+
+		if names := listDir(t, dir); len(names) != 1 {
+			t.Errorf("expected temporary file to be removed, got %v", names)
+		}
+	})
+}
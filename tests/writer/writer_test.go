@@ -1,97 +0,0 @@
-```go
-package writer
-
-import (
-	"encoding/json"
-	"io/ioutil"
-	"os"
-	"testing"
-)
-
-func TestWriteAnswer(t *testing.T) {
-	t.Run("valid input scenario", func(t *testing.T) {
-		w := New()
-		w.File = "test.json"
-		w.Response = map[string]string{"key": "value"}
-
-		err := WriteAnswer(w)
-		if err != nil {
-			t.Errorf("expected nil, got %v", err)
-		}
-
-		data, _ := ioutil.ReadFile("test.json")
-		if string(data) != `{"key":"value"}` {
-			t.Errorf("expected {\"key\":\"value\"}, got %s", data)
-		}
-
-		os.Remove("test.json")
-	})
-
-	t.Run("invalid input scenario", func(t *testing.T) {
-		w := New()
-		w.File = "test.json"
-		w.Response = make(chan int)
-
-		err := WriteAnswer(w)
-		if err == nil {
-			t.Errorf("expected error, got nil")
-		}
-
-		os.Remove("test.json")
-	})
-
-	t.Run("file writing scenario", func(t *testing.T) {
-		w := New()
-		w.File = "/invalid/path/test.json"
-		w.Response = "test"
-
-		err := WriteAnswer(w)
-		if err == nil {
-			t.Errorf("expected error, got nil")
-		}
-	})
-
-	t.Run("edge cases", func(t *testing.T) {
-		w := New()
-		w.File = ""
-		w.Response = "test"
-
-		err := WriteAnswer(w)
-		if err == nil {
-			t.Errorf("expected error, got nil")
-		}
-	})
-
-	t.Run("concurrency scenarios", func(t *testing.T) {
-		w := New()
-		w.File = "test.json"
-		w.Response = "test"
-
-		go func() {
-			err := WriteAnswer(w)
-			if err != nil {
-				t.Errorf("expected nil, got %v", err)
-			}
-		}()
-
-		err := WriteAnswer(w)
-		if err != nil {
-			t.Errorf("expected nil, got %v", err)
-		}
-
-		os.Remove("test.json")
-	})
-
-	t.Run("error handling", func(t *testing.T) {
-		w := New()
-		w.File = "/invalid/path/test.json"
-		w.Response = "test"
-
-		err := WriteAnswer(w)
-		if err == nil {
-			t.Errorf("expected error, got nil")
-		}
-	})
-}
-```
-This test suite covers all the scenarios mentioned in the task. It tests the function with valid and invalid inputs, checks the file writing functionality, handles edge cases, tests the function in a concurrent environment, and checks the error handling of the function.
\ No newline at end of file
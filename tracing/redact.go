@@ -0,0 +1,72 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"github.com/wesleyholiveira/caesar-challenge/logging"
+)
+
+// redactingExporter passes the spans through logging.Redact before exporting them, the http
+// client spans hold the request URL and its token query parameter in url.full
+type redactingExporter struct {
+	sdktrace.SpanExporter
+}
+
+func (e redactingExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	redacted := make([]sdktrace.ReadOnlySpan, len(spans))
+	for i, s := range spans {
+		redacted[i] = redactedSpan{s}
+	}
+
+	return e.SpanExporter.ExportSpans(ctx, redacted)
+}
+
+// redactedSpan redacts the name, attributes, events and status of the span it wraps
+type redactedSpan struct {
+	sdktrace.ReadOnlySpan
+}
+
+func (s redactedSpan) Name() string {
+	return logging.Redact(s.ReadOnlySpan.Name())
+}
+
+func (s redactedSpan) Attributes() []attribute.KeyValue {
+	return redactAttributes(s.ReadOnlySpan.Attributes())
+}
+
+func (s redactedSpan) Events() []sdktrace.Event {
+	events := s.ReadOnlySpan.Events()
+	redacted := make([]sdktrace.Event, len(events))
+	for i, e := range events {
+		e.Name = logging.Redact(e.Name)
+		e.Attributes = redactAttributes(e.Attributes)
+		redacted[i] = e
+	}
+
+	return redacted
+}
+
+func (s redactedSpan) Status() sdktrace.Status {
+	status := s.ReadOnlySpan.Status()
+	status.Description = logging.Redact(status.Description)
+	return status
+}
+
+// redactAttributes redacts the string values, attributes named token are masked whatever their value
+func redactAttributes(attrs []attribute.KeyValue) []attribute.KeyValue {
+	redacted := make([]attribute.KeyValue, len(attrs))
+	for i, a := range attrs {
+		switch {
+		case a.Key == "token":
+			a.Value = attribute.StringValue(logging.Mask)
+		case a.Value.Type() == attribute.STRING:
+			a.Value = attribute.StringValue(logging.Redact(a.Value.AsString()))
+		}
+		redacted[i] = a
+	}
+
+	return redacted
+}
@@ -0,0 +1,116 @@
+package verify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/wesleyholiveira/caesar-challenge/crypto"
+	"github.com/wesleyholiveira/caesar-challenge/format"
+	"github.com/wesleyholiveira/caesar-challenge/model"
+	"github.com/wesleyholiveira/caesar-challenge/reader"
+	"github.com/wesleyholiveira/caesar-challenge/tracing"
+)
+
+// ErrMismatch is returned when the stored answer disagrees with the recomputed one
+var ErrMismatch = errors.New("answer does not match its recomputed decryption")
+
+// Diff is a field whose stored value differs from the recomputed one
+type Diff struct {
+	Field    string
+	Stored   string
+	Expected string
+	// Message tells what is wrong with a field that has no recomputed value, such as a missing token
+	Message string
+}
+
+func (d Diff) String() string {
+	if d.Message != "" {
+		return fmt.Sprintf("%s: %s", d.Field, d.Message)
+	}
+
+	return fmt.Sprintf("%s: stored %q, expected %q", d.Field, d.Stored, d.Expected)
+}
+
+// Report is the result of verifying an answer
+type Report struct {
+	File   string
+	Answer *model.ChallengeResponse
+	Diffs  []Diff
+}
+
+// OK reports whether the stored answer matches the recomputed one
+func (r *Report) OK() bool {
+	return len(r.Diffs) == 0
+}
+
+// Err returns ErrMismatch wrapped with the differences, or nil when the answer is OK
+func (r *Report) Err() error {
+	if r.OK() {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s", ErrMismatch, r)
+}
+
+func (r *Report) String() string {
+	if r.OK() {
+		return "ok"
+	}
+
+	lines := make([]string, len(r.Diffs))
+	for i, d := range r.Diffs {
+		lines[i] = d.String()
+	}
+
+	return strings.Join(lines, "; ")
+}
+
+// Answer checks the answer with reader.Validate and returns a diff for every problem found,
+// with decifrado recomputed from cifrado and numero_casas, or the pipeline it records, and resumo_criptografico from decifrado
+func Answer(r *model.ChallengeResponse) []Diff {
+	diffs := []Diff{}
+	for _, p := range reader.Validate(r) {
+		diffs = append(diffs, diff(r, p))
+	}
+
+	return diffs
+}
+
+// diff returns the stored and expected values of the field of the problem
+func diff(r *model.ChallengeResponse, p reader.Problem) Diff {
+	decrypted, _ := crypto.DecryptAnswer(r)
+
+	switch {
+	case p.Field == "numero_casas":
+		return Diff{Field: p.Field, Stored: strconv.Itoa(r.Places), Expected: fmt.Sprintf("[%d, %d]", crypto.MinPlaces, crypto.MaxPlaces)}
+	case p.Field == "decifrado" && r.CryptedText != "":
+		return Diff{Field: p.Field, Stored: r.DecryptedText, Expected: decrypted}
+	case p.Field == "resumo_criptografico" && r.DecryptedText != "":
+		return Diff{Field: p.Field, Stored: r.SummaryCrypto, Expected: crypto.Summary(r.DecryptedText)}
+	case p.Field == "resumo_criptografico" && r.CryptedText != "":
+		return Diff{Field: p.Field, Stored: r.SummaryCrypto, Expected: crypto.Summary(decrypted)}
+	}
+
+	return Diff{Field: p.Field, Message: p.Message}
+}
+
+// File reads the answer file with dec, see reader.ReadChallenge, and verifies it
+func File(f string, dec format.Decoder) (*Report, error) {
+	return FileContext(context.Background(), f, dec)
+}
+
+// FileContext is File traced as a span of ctx
+func FileContext(ctx context.Context, f string, dec format.Decoder) (_ *Report, err error) {
+	ctx, span := tracing.Start(ctx, "verify.File")
+	defer func() { tracing.End(span, err) }()
+
+	response, err := reader.ReadChallengeContext(ctx, f, dec)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Report{File: f, Answer: response, Diffs: Answer(response)}, nil
+}
@@ -0,0 +1,306 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/wesleyholiveira/caesar-challenge/config"
+	"github.com/wesleyholiveira/caesar-challenge/crypto"
+	"github.com/wesleyholiveira/caesar-challenge/format"
+	"github.com/wesleyholiveira/caesar-challenge/logging"
+	"github.com/wesleyholiveira/caesar-challenge/metrics"
+	"github.com/wesleyholiveira/caesar-challenge/model"
+	"github.com/wesleyholiveira/caesar-challenge/reader"
+	"github.com/wesleyholiveira/caesar-challenge/writer"
+)
+
+// Folders inside the watched directory processed inputs are moved to
+const (
+	DoneDir   = "done"
+	FailedDir = "failed"
+)
+
+// Defaults used when the matching Watcher fields are not set
+const (
+	DefaultInterval = 2 * time.Second
+	DefaultSettle   = time.Second
+)
+
+// AnswerSuffix is appended to the input name, before the format extension, to name its answer
+const AnswerSuffix = ".answer"
+
+// ErrorSuffix is appended to the name of a failed input to name the file holding its error
+const ErrorSuffix = ".error"
+
+// ErrPlaces is returned by Validate, Scan and Run when Places is outside of the accepted shifts
+var ErrPlaces = errors.New("places is out of range")
+
+// Result reports what happened to an input file
+type Result struct {
+	// File is where the input was moved to
+	File string
+	// Answer is the answer file written next to it, empty on failure
+	Answer string
+	Err    error
+}
+
+// Watcher polls a directory for ciphertext files, decrypts each one into an answer file and
+// moves the input with its answer to DoneDir, or to FailedDir with its error
+type Watcher struct {
+	Dir string
+	// Interval between scans, defaults to DefaultInterval
+	Interval time.Duration
+	// Settle is how long a file must stay unmodified before it is processed so files
+	// still being written are left for a later scan, defaults to DefaultSettle
+	Settle time.Duration
+	// Places decrypts every file with this shift, the files are cracked when it is zero.
+	// Otherwise it must be within crypto.MinPlaces and crypto.MaxPlaces, see Validate.
+	Places int
+	// Format of the answer files, resolved from config.AnswerFormat when nil
+	Format format.Format
+	// Report is called with the result of every processed file when set
+	Report func(Result)
+	// Logger receives a line for every processed file, nothing is logged when it is nil
+	Logger *slog.Logger
+}
+
+// New returns a Watcher of dir with the default timings
+func New(dir string) *Watcher {
+	return &Watcher{Dir: dir, Interval: DefaultInterval, Settle: DefaultSettle}
+}
+
+// Run scans the directory every Interval until ctx is done
+func (w *Watcher) Run(ctx context.Context) error {
+	interval := w.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		results, err := w.Scan()
+		if err != nil {
+			return err
+		}
+
+		if w.Report != nil {
+			for _, r := range results {
+				w.Report(r)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Validate checks the settings of the watcher before any file is processed
+func (w *Watcher) Validate() error {
+	if w.Places != 0 && (w.Places < crypto.MinPlaces || w.Places > crypto.MaxPlaces) {
+		return fmt.Errorf("%w: %d, expected 0 to crack or [%d, %d]", ErrPlaces, w.Places, crypto.MinPlaces, crypto.MaxPlaces)
+	}
+
+	return nil
+}
+
+// Scan processes the files of the directory that settled, in name order.
+// Hidden files, such as the temporary files of writer, and folders are skipped.
+// An input named like one already in DoneDir or FailedDir is stored with -1, -2 and so on
+// before its extension instead of replacing it.
+func (w *Watcher) Scan() ([]Result, error) {
+	if err := w.Validate(); err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(w.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	settle := w.Settle
+	if settle <= 0 {
+		settle = DefaultSettle
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	results := []Result{}
+	for _, e := range entries {
+		if !e.Type().IsRegular() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+
+		info, err := e.Info()
+		if os.IsNotExist(err) {
+			continue
+		}
+
+		if err != nil {
+			return results, err
+		}
+
+		if time.Since(info.ModTime()) < settle {
+			continue
+		}
+
+		r, err := w.process(e.Name())
+		if err != nil {
+			return results, err
+		}
+
+		results = append(results, r)
+	}
+
+	return results, nil
+}
+
+// process decrypts the named input and moves it, an error is only returned when the
+// input could not be moved so the scan stops instead of retrying it forever
+func (w *Watcher) process(name string) (Result, error) {
+	f, err := w.format()
+	if err != nil {
+		return Result{}, err
+	}
+
+	answerSuffix := AnswerSuffix + ".json"
+	if exts := f.Extensions(); len(exts) > 0 {
+		answerSuffix = AnswerSuffix + exts[0]
+	}
+
+	stored, err := w.free(DoneDir, name, answerSuffix)
+	if err != nil {
+		return Result{Err: err}, err
+	}
+
+	logger := logging.Or(w.Logger).With("file", name)
+	answer, err := w.decrypt(name, filepath.Join(w.Dir, DoneDir, stored+answerSuffix), f, logger)
+	if err == nil {
+		file, moveErr := w.move(name, DoneDir, stored)
+		if moveErr == nil {
+			logger.Info("file decrypted", "answer", answer)
+		}
+
+		return Result{File: file, Answer: answer, Err: moveErr}, moveErr
+	}
+
+	logger.Warn("file failed", "error", err)
+
+	stored, freeErr := w.free(FailedDir, name, ErrorSuffix)
+	if freeErr != nil {
+		return Result{Err: freeErr}, freeErr
+	}
+
+	file, moveErr := w.move(name, FailedDir, stored)
+	if moveErr != nil {
+		return Result{Err: moveErr}, moveErr
+	}
+
+	if writeErr := os.WriteFile(file+ErrorSuffix, []byte(err.Error()+"\n"), 0644); writeErr != nil {
+		return Result{File: file, Err: writeErr}, writeErr
+	}
+
+	return Result{File: file, Err: err}, nil
+}
+
+// decrypt writes the answer of the named input to the answer file and returns its path
+func (w *Watcher) decrypt(name, file string, f format.Format, logger *slog.Logger) (string, error) {
+	data, err := reader.ReadFile(filepath.Join(w.Dir, name), config.MaxInputSize)
+	if err != nil {
+		return "", err
+	}
+
+	response := &model.ChallengeResponse{Places: w.Places, CryptedText: strings.TrimRight(string(data), "\n")}
+	if strings.TrimSpace(response.CryptedText) == "" {
+		return "", errors.New("file is empty")
+	}
+
+	if response.Places == 0 {
+		candidates := crypto.Crack(response.CryptedText)
+		metrics.ObserveCrack(len(candidates))
+		response.Places = candidates[0].Places
+	}
+
+	answer := writer.New()
+	answer.File = file
+	answer.Format = f
+	answer.Response = response
+	answer.Logger = logger
+	if err := crypto.Decrypt(answer); err != nil {
+		return "", err
+	}
+
+	metrics.ObserveDecrypt(response.CryptedText)
+	return answer.File, nil
+}
+
+// move renames the named input into the folder as stored and returns its new path
+func (w *Watcher) move(name, folder, stored string) (string, error) {
+	target := filepath.Join(w.Dir, folder, stored)
+	return target, os.Rename(filepath.Join(w.Dir, name), target)
+}
+
+// free creates the folder and returns the name the input is stored under in it: its own
+// name, or the name with -1, -2 and so on before its extension when an earlier input of the
+// same name, or the file named with its suffix, is already there. Nothing is overwritten.
+func (w *Watcher) free(folder, name, suffix string) (string, error) {
+	dir := filepath.Join(w.Dir, folder)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+
+	ext := filepath.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+	stored := name
+	for i := 1; ; i++ {
+		taken, err := exists(filepath.Join(dir, stored), filepath.Join(dir, stored+suffix))
+		if err != nil {
+			return "", err
+		}
+
+		if !taken {
+			return stored, nil
+		}
+
+		stored = fmt.Sprintf("%s-%d%s", stem, i, ext)
+	}
+}
+
+// exists reports whether any of the paths exists
+func exists(paths ...string) (bool, error) {
+	for _, p := range paths {
+		_, err := os.Lstat(p)
+		if err == nil {
+			return true, nil
+		}
+
+		if !os.IsNotExist(err) {
+			return false, err
+		}
+	}
+
+	return false, nil
+}
+
+func (w *Watcher) format() (format.Format, error) {
+	if w.Format != nil {
+		return w.Format, nil
+	}
+
+	if config.AnswerFormat != "" {
+		return format.Lookup(config.AnswerFormat)
+	}
+
+	return format.JSON, nil
+}
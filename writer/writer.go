@@ -1,28 +1,168 @@
 package writer
 
 import (
-	"encoding/json"
-	"io/ioutil"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/wesleyholiveira/caesar-challenge/config"
+	"github.com/wesleyholiveira/caesar-challenge/format"
+	"github.com/wesleyholiveira/caesar-challenge/logging"
+	"github.com/wesleyholiveira/caesar-challenge/tracing"
 )
 
+// BackupSuffix is appended to the answer file name when a backup of the previous answer is kept
+const BackupSuffix = ".bak"
+
+// File is the subset of *os.File used while writing the temporary answer file
+type File interface {
+	io.Writer
+	Name() string
+	Sync() error
+	Close() error
+}
+
 type WriterAnswer struct {
 	File     string
 	Response interface{}
 	Data     []byte
+	// Format encodes the response, defaults to config.AnswerFormat or the one matching File
+	Format format.Format
+	// Backup keeps the previous answer as File + BackupSuffix before replacing it
+	Backup bool
+	// CreateTemp opens the temporary file the answer is written to, defaults to os.CreateTemp
+	CreateTemp func(dir, pattern string) (File, error)
+	// Logger receives a line for every answer written, and for the decryption done by crypto.Decrypt.
+	// Nothing is logged when it is nil.
+	Logger *slog.Logger
 }
 
 func New() *WriterAnswer {
 	return &WriterAnswer{}
 }
 
+func createTemp(dir, pattern string) (File, error) {
+	return os.CreateTemp(dir, pattern)
+}
+
+// WriteAnswer encodes the response and atomically replaces the answer file with it.
+// The data is written to a temporary file in the same directory, synced to disk and
+// then renamed over w.File, so readers never see a partially written answer.
 func WriteAnswer(w *WriterAnswer) error {
-	strStruct, err := json.Marshal(w.Response)
+	return WriteAnswerContext(context.Background(), w)
+}
+
+// WriteAnswerContext is WriteAnswer traced as a span of ctx
+func WriteAnswerContext(ctx context.Context, w *WriterAnswer) (err error) {
+	_, span := tracing.Start(ctx, "writer.WriteAnswer", attribute.String("file", w.File))
+	defer func() { tracing.End(span, err) }()
+
+	f := w.Format
+	if f == nil {
+		var err error
+		if f, err = format.Resolve(config.AnswerFormat, w.File); err != nil {
+			return err
+		}
+	}
+
+	strStruct, err := f.Encode(w.Response)
+
+	if err != nil {
+		return err
+	}
+
+	create := w.CreateTemp
+	if create == nil {
+		create = createTemp
+	}
+
+	if err := writeAtomic(w.File, strStruct, w.Backup, create); err != nil {
+		return err
+	}
 
+	span.SetAttributes(attribute.String("format", f.Name()), attribute.Int("bytes", len(strStruct)))
+	logging.Or(w.Logger).Debug("answer written", "file", w.File, "format", f.Name(), "bytes", len(strStruct))
+	return nil
+}
+
+func writeAtomic(file string, data []byte, backup bool, create func(string, string) (File, error)) (err error) {
+	dir, base := filepath.Split(file)
+	if dir == "" {
+		dir = "."
+	}
+
+	tmp, err := create(dir, "."+base+".*.tmp")
 	if err != nil {
 		return err
 	}
 
-	if err := ioutil.WriteFile(w.File, strStruct, 0755); err != nil {
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	if _, err = tmp.Write(data); err != nil {
+		return err
+	}
+
+	if err = tmp.Sync(); err != nil {
+		return err
+	}
+
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	if err = os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+
+	if backup {
+		if err = backupFile(file); err != nil {
+			return err
+		}
+	}
+
+	if err = os.Rename(tmp.Name(), file); err != nil {
+		return err
+	}
+
+	return syncDir(dir)
+}
+
+// backupFile copies the current answer, if any, to file + BackupSuffix
+func backupFile(file string) error {
+	data, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return writeAtomic(file+BackupSuffix, data, false, createTemp)
+}
+
+// syncDir flushes the directory entry so the rename survives a crash
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+
+	defer d.Close()
+
+	// Some filesystems do not support syncing directories
+	if err := d.Sync(); err != nil && !errors.Is(err, syscall.EINVAL) {
 		return err
 	}
 